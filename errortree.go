@@ -12,6 +12,8 @@
 package errortree
 
 import (
+	"errors"
+	"reflect"
 	"sort"
 )
 
@@ -19,13 +21,27 @@ import (
 // for representing paths to nested Errors
 const DefaultDelimiter = ":"
 
+var (
+	// ErrEmptyPath is returned by TrySetPath and TryAddPath if the provided path is empty.
+	ErrEmptyPath = errors.New("empty path")
+	// ErrKeyExists is returned by TryAdd and TryAddPath if the key is already present.
+	ErrKeyExists = errors.New("key exists")
+	// ErrFrozen is returned by TrySet, TryAdd and their path variants if the tree,
+	// or a tree on the path, has been frozen using Tree.Freeze.
+	ErrFrozen = errors.New("tree is frozen")
+)
+
 // Keys returns all error keys present in a given tree.
 //
-// The value returned by this function is an alphabetically sorted, flattened
-// list of all keys in a tree of Tree structs.
+// The value returned by this function is a flattened list of all keys in a tree
+// of Tree structs, ordered according to the Ordering and Less comparator of the
+// top-level tree. By default, keys are sorted in natural order: keys are sorted
+// alphabetically, except for sequences of digits, which are compared by their
+// numeric value.
 //
 // The delimiter configured for the top-level tree is guaranteed to be used
-// throughout the complete tree.
+// throughout the complete tree. Occurrences of the delimiter inside of keys are
+// escaped using EscapeKey, so each key can be split using ParsePath.
 func Keys(err error) []string {
 	tree, isTree := GetTree(err)
 
@@ -34,68 +50,361 @@ func Keys(err error) []string {
 		return nil
 	}
 
-	flattened := flatten(tree, tree.getDelimiter(), nil)
-	keys := make([]string, 0, len(flattened))
-	for key := range flattened {
-		keys = append(keys, key)
-	}
-
-	// Sort the keys before returning them
-	sort.Strings(keys)
-
+	keys, _ := flattenOrdered(tree)
 	return keys
 }
 
-func set(tree *Tree, key string, err error) *Tree {
+// setMode defines how set handles an existing error under the given path.
+type setMode int
+
+const (
+	// modeSet replaces an existing error
+	modeSet setMode = iota
+	// modeAdd fails if an error exists
+	modeAdd
+	// modeAppend combines the existing error with the new error
+	modeAppend
+	// modeNodeError replaces the node-level error of the tree under the path
+	modeNodeError
+)
+
+// set stores an error under the given path, creating intermediate trees as needed.
+//
+// Errors on the path which are not trees are converted into trees holding the
+// error as node-level error. In modeNodeError, the path may be empty.
+// The tree is not modified if an error is returned.
+func set(tree *Tree, path Path, err error, mode setMode) (*Tree, error) {
 	if err == nil {
-		return tree
+		return tree, nil
 	}
 
 	if tree == nil {
 		tree = New()
 	}
 
-	errors := tree.getErrors()
-	errors[key] = err
+	// Appending and setting node-level errors modify an existing tree under the path,
+	// while other modes replace the error stored under the path
+	if isFrozen(tree, path, mode == modeAppend || mode == modeNodeError) {
+		return nil, ErrFrozen
+	}
 
-	return tree
+	var existing error
+	var keyExists bool
+	if mode == modeNodeError {
+		existing = lookupNodeError(tree, path)
+		keyExists = existing != nil
+	} else {
+		existing, keyExists = lookup(tree, path)
+	}
+	if mode == modeAdd && keyExists {
+		return nil, newPathError(tree, path, ErrKeyExists)
+	}
+
+	// Count the error towards the tree's limit, dropping it if the limit would be exceeded
+	added := errorCount(err)
+	delta := added
+	if (mode == modeSet || mode == modeNodeError) && keyExists {
+		delta -= errorCount(existing)
+	}
+	if tree.Limit > 0 && delta > 0 && tree.stored+delta > tree.Limit {
+		tree.dropped += added
+		return tree, nil
+	}
+	tree.stored += delta
+
+	// Follow the path, creating intermediate trees as needed
+	intermediate := path
+	if mode != modeNodeError {
+		intermediate = path[:len(path)-1]
+	}
+	parent := tree
+	for _, key := range intermediate {
+		child := parent.getErrors()[key]
+		childTree, isTree := GetTree(child)
+		if !isTree {
+			childTree = newChild(parent)
+			childTree.NodeError = child
+			parent.put(key, childTree)
+		}
+		parent = childTree
+	}
+	if mode == modeNodeError {
+		parent.NodeError = err
+		return tree, nil
+	}
+
+	key := path[len(path)-1]
+	switch {
+	case mode == modeAppend && keyExists:
+		if existingTree, isTree := GetTree(existing); isTree {
+			existingTree.NodeError = appendError(existingTree.NodeError, err)
+		} else {
+			parent.put(key, appendError(existing, err))
+		}
+	default:
+		parent.put(key, err)
+	}
+
+	return tree, nil
+}
+
+// lookup returns the error stored under the given path and whether the path exists,
+// without modifying the tree.
+func lookup(tree *Tree, path Path) (error, bool) {
+	for _, key := range path[:len(path)-1] {
+		childTree, isTree := GetTree(tree.Errors[key])
+		if !isTree {
+			return nil, false
+		}
+		tree = childTree
+	}
+
+	err, keyExists := tree.Errors[path[len(path)-1]]
+	return err, keyExists
+}
+
+// lookupNodeError returns the error which becomes the node-level error of the tree
+// under the given path, without modifying the tree. This is either the node-level error
+// of an existing tree or an existing error which is not a tree.
+func lookupNodeError(tree *Tree, path Path) error {
+	var err error = tree
+	for _, key := range path {
+		childTree, isTree := GetTree(err)
+		if !isTree {
+			return nil
+		}
+		err = childTree.Errors[key]
+	}
+
+	if childTree, isTree := GetTree(err); isTree {
+		return childTree.NodeError
+	}
+	return err
+}
+
+// isFrozen reports whether the given tree or any existing tree on the given path is frozen.
+//
+// The existing tree under the complete path is only checked if includeTarget is set.
+func isFrozen(tree *Tree, path Path, includeTarget bool) bool {
+	for _, key := range path {
+		if tree.frozen {
+			return true
+		}
+
+		childTree, isTree := GetTree(tree.Errors[key])
+		if !isTree {
+			return false
+		}
+		tree = childTree
+	}
+
+	return includeTarget && tree.frozen
+}
+
+// errorCount returns the number of errors held by the given error, as reported by formatters.
+func errorCount(err error) int {
+	tree, isTree := GetTree(err)
+	if !isTree {
+		return len(splitErrors(err))
+	}
+
+	count := 0
+	for _, leaf := range walk(tree, tree, nil, nil) {
+		count += len(splitErrors(leaf.err))
+	}
+	return count
+}
+
+// newPathError returns a *PathError for the given path, using the delimiter of the tree.
+func newPathError(tree *Tree, path Path, err error) *PathError {
+	return &PathError{
+		path:      append(Path(nil), path...),
+		delimiter: tree.getDelimiter(),
+		err:       err,
+	}
 }
 
 // Set creates or replaces an error under a given key in a tree.
 //
 // The parent value may be nil, in which case a new *Tree is created, to which the
 // key is added and the new *Tree is returned.
+// If the parent is an error which is not a *Tree, a new *Tree holding the parent
+// as node-level error is created, to which the key is added and the new *Tree is returned.
 // Otherwise the *Tree to which the key was added is returned.
+// If err is nil, the parent is returned as it is.
 func Set(parent error, key string, err error) error {
-	tree, isTree := GetTree(parent)
-
-	// Add only works on an *errortree.Tree, panic if we received another error
-	if parent != nil && !isTree {
-		panic("Cannot set error: not an *errortree.Tree.")
-	}
+	return SetPath(parent, Path{key}, err)
+}
 
-	if tree = set(tree, key, err); tree != nil {
-		return tree
-	}
-	return nil
+// SetPath creates or replaces an error under a given path in a tree.
+//
+// Intermediate trees on the path are created as needed. Errors on the path which
+// are not trees are converted into trees holding the error as node-level error.
+// This function panics if the path is empty or if the tree, or a tree on the path,
+// is frozen (see Tree.Freeze). TrySetPath may be used for handling these cases
+// without panicking.
+// Otherwise it behaves like Set.
+func SetPath(parent error, path Path, err error) error {
+	return setPath(parent, path, err, modeSet)
 }
 
 // Add adds an error under a given key to the provided tree.
 //
 // This function panics if the key is already present in the tree.
+// TryAdd may be used for handling this case without panicking.
 // Otherwise it behaves like Set.
 func Add(parent error, key string, err error) error {
+	return AddPath(parent, Path{key}, err)
+}
+
+// AddPath adds an error under a given path to the provided tree.
+//
+// This function panics if the path is already present in the tree.
+// Otherwise it behaves like SetPath.
+func AddPath(parent error, path Path, err error) error {
+	return setPath(parent, path, err, modeAdd)
+}
+
+// Append adds an error under a given key to the provided tree, keeping any
+// error already present under the key.
+//
+// If the key already holds an error, both errors are combined into a MultiError,
+// which Get returns and formatters report as separate errors under the key.
+// If the key holds a *Tree, the error is appended to the tree's node-level error.
+// Otherwise it behaves like Set.
+func Append(parent error, key string, err error) error {
+	return AppendPath(parent, Path{key}, err)
+}
+
+// AppendPath adds an error under a given path to the provided tree, keeping any
+// error already present under the path, like Append does.
+//
+// Otherwise it behaves like SetPath.
+func AppendPath(parent error, path Path, err error) error {
+	return setPath(parent, path, err, modeAppend)
+}
+
+// TrySet creates or replaces an error under a given key in a tree, like Set does.
+//
+// In contrast to Set, the returned tree is the converted tree if the parent is not
+// a *Tree, even if err is nil. The returned tree is nil if both the parent and err are nil.
+// If the parent is a *SyncTree, it is modified while holding its lock and the
+// returned tree is a snapshot of it.
+func TrySet(parent error, key string, err error) (*Tree, error) {
+	return TrySetPath(parent, Path{key}, err)
+}
+
+// TrySetPath creates or replaces an error under a given path in a tree, like SetPath does.
+//
+// Instead of panicking, this function returns ErrEmptyPath if the path is empty
+// and ErrFrozen if the tree, or a tree on the path, is frozen.
+// The tree is not modified if an error is returned.
+func TrySetPath(parent error, path Path, err error) (*Tree, error) {
+	return trySetPath(parent, path, err, modeSet)
+}
+
+// TryAdd adds an error under a given key to the provided tree, like Add does.
+//
+// Instead of panicking, this function returns an error wrapping ErrKeyExists if the key
+// is already present in the tree. Otherwise it behaves like TrySet.
+func TryAdd(parent error, key string, err error) (*Tree, error) {
+	return TryAddPath(parent, Path{key}, err)
+}
+
+// TryAddPath adds an error under a given path to the provided tree, like AddPath does.
+//
+// Instead of panicking, this function returns a *PathError wrapping ErrKeyExists
+// if the path is already present in the tree. Otherwise it behaves like TrySetPath.
+func TryAddPath(parent error, path Path, err error) (*Tree, error) {
+	return trySetPath(parent, path, err, modeAdd)
+}
+
+func trySetPath(parent error, path Path, err error, mode setMode) (*Tree, error) {
+	if len(path) == 0 {
+		return nil, ErrEmptyPath
+	}
+
+	if syncTree, isSync := parent.(*SyncTree); isSync {
+		var snapshot *Tree
+		var setErr error
+		syncTree.mutate(func(tree *Tree) {
+			_, setErr = set(tree, path, err, mode)
+			snapshot = copyTree(tree, make(map[*Tree]*Tree))
+		})
+		return snapshot, setErr
+	}
+
+	return set(toTree(parent), path, err, mode)
+}
+
+// setPath is the strict variant of trySetPath, which panics instead of returning an error.
+func setPath(parent error, path Path, err error, mode setMode) error {
+	if syncTree, isSync := parent.(*SyncTree); isSync {
+		syncTree.mutate(func(tree *Tree) {
+			setPath(tree, path, err, mode)
+		})
+		return syncTree
+	}
+
+	tree, setErr := trySetPath(parent, path, err, mode)
+	switch {
+	case errors.Is(setErr, ErrEmptyPath):
+		panic("Cannot set error: empty path.")
+	case errors.Is(setErr, ErrFrozen):
+		panic("Cannot set error: tree is frozen.")
+	case errors.Is(setErr, ErrKeyExists):
+		panic("Cannot add error: key " + setErr.(*PathError).Key() + " exists.")
+	}
+
+	// Keep the parent as it is if there is nothing to set
+	if err == nil {
+		return parent
+	}
+	return tree
+}
+
+// toTree returns the tree for the given parent error.
+//
+// If the parent is neither nil nor a *Tree, a new *Tree holding the parent
+// as node-level error is returned.
+func toTree(parent error) *Tree {
+	if parent == nil {
+		return nil
+	}
+
 	tree, isTree := GetTree(parent)
-	if tree != nil && isTree {
-		if _, keyExists := tree.getErrors()[key]; keyExists {
-			panic("Cannot add error: key " + key + " exists.")
-		}
+	if !isTree {
+		tree = New()
+		tree.NodeError = parent
+	}
+	return tree
+}
+
+// SetNodeError sets the node-level error of the tree under the given path.
+//
+// Trees on the path are created as needed. Errors on the path which are not
+// trees are converted into trees holding the error as node-level error,
+// like Unflatten does. If the path is empty, the node-level error of the
+// parent tree itself is set.
+// Otherwise this function behaves like SetPath.
+func SetNodeError(parent error, path Path, err error) error {
+	if syncTree, isSync := parent.(*SyncTree); isSync {
+		syncTree.mutate(func(tree *Tree) {
+			SetNodeError(tree, path, err)
+		})
+		return syncTree
 	}
 
-	if tree = set(tree, key, err); tree != nil {
-		return tree
+	if err == nil {
+		return parent
 	}
-	return nil
+
+	tree, setErr := set(toTree(parent), path, err, modeNodeError)
+	if errors.Is(setErr, ErrFrozen) {
+		panic("Cannot set error: tree is frozen.")
+	}
+
+	return tree
 }
 
 // Get retrieves the error for the given key from the provided error.
@@ -128,6 +437,99 @@ func GetAny(err error, key string, path ...string) error {
 	return get(tree, true, key, path...)
 }
 
+// GetPath retrieves the error under the given path from the provided error.
+//
+// GetPath behaves like Get and additionally returns nil if the path is empty.
+func GetPath(err error, path Path) error {
+	if len(path) == 0 {
+		return nil
+	}
+
+	return Get(err, path[0], path[1:]...)
+}
+
+// GetAnyPath retrieves the error under the given path from the provided error.
+//
+// GetAnyPath behaves like GetAny and additionally returns the provided error
+// if the path is empty.
+func GetAnyPath(err error, path Path) error {
+	if len(path) == 0 {
+		return err
+	}
+
+	return GetAny(err, path[0], path[1:]...)
+}
+
+// GetPointer retrieves the error referenced by the given JSON Pointer,
+// as defined by RFC 6901, from the provided error.
+//
+// GetPointer behaves like GetPath and additionally returns nil if the pointer is invalid.
+func GetPointer(err error, pointer string) error {
+	path, parseErr := ParsePointer(pointer)
+	if parseErr != nil {
+		return nil
+	}
+
+	return GetPath(err, path)
+}
+
+// Lookup retrieves the error for the given key from the provided error
+// and wraps it in a *PathError, which carries the path of the error inside the tree.
+// The path parameter may be used for specifying a nested error's key.
+//
+// Lookup behaves like Get and returns nil if the error cannot be found.
+func Lookup(err error, key string, path ...string) *PathError {
+	tree, isTree := GetTree(err)
+	if !isTree {
+		return nil
+	}
+
+	child := get(tree, false, key, path...)
+	if child == nil {
+		return nil
+	}
+
+	return &PathError{
+		path:      append(Path{key}, path...),
+		delimiter: tree.getDelimiter(),
+		err:       child,
+	}
+}
+
+// Leaves returns all errors inside a given tree, each wrapped in a *PathError
+// which carries the path of the error inside the tree.
+//
+// The returned errors are sorted in the same way as the keys returned by Keys.
+// If the provided error is not a *Tree, nil is returned.
+func Leaves(err error) []*PathError {
+	tree, isTree := GetTree(err)
+	if !isTree {
+		return nil
+	}
+
+	return sortedLeaves(tree)
+}
+
+// sortedLeaves returns all errors inside a tree, ordered according to the
+// tree's Ordering and comparator.
+func sortedLeaves(tree *Tree) []*PathError {
+	// Leaves are collected in insertion order already
+	leaves := walk(tree, tree, nil, nil)
+	if tree.Ordering != InsertionOrder {
+		sort.SliceStable(leaves, func(i, j int) bool {
+			return naturalLess(leaves[i].Key(), leaves[j].Key())
+		})
+	}
+
+	if tree.Less != nil {
+		sort.SliceStable(leaves, func(i, j int) bool {
+			return tree.Less(leaves[i], leaves[j])
+		})
+	}
+
+	return leaves
+}
+
 func get(tree *Tree, returnAnyChild bool, key string, path ...string) error {
 	child, keyExists := tree.getErrors()[key]
 	if !keyExists {
@@ -152,41 +554,225 @@ func get(tree *Tree, returnAnyChild bool, key string, path ...string) error {
 	return childErr
 }
 
+// Find returns the keys of all errors in a given tree which match the target
+// error, as reported by errors.Is.
+//
+// The returned keys are flattened and sorted in the same way as the keys
+// returned by Keys.
+// If the provided error is not a *Tree, nil is returned.
+func Find(err error, target error) []string {
+	return find(err, func(child error) bool {
+		return errors.Is(child, target)
+	})
+}
+
+// FindAs returns the keys of all errors in a given tree which match the type
+// of target, as reported by errors.As.
+//
+// If at least one error matches, target is set to the error stored under the
+// first returned key.
+// Like errors.As, this function panics if target is not a non-nil pointer to
+// either a type that implements error, or to any interface type.
+func FindAs(err error, target interface{}) []string {
+	if value := reflect.ValueOf(target); target == nil || value.Kind() != reflect.Ptr || value.IsNil() {
+		panic("errortree: target must be a non-nil pointer")
+	}
+
+	// Only the first match is stored in target, subsequent matches are
+	// checked against a scratch value of the same type.
+	scratch := reflect.New(reflect.TypeOf(target).Elem()).Interface()
+	matched := false
+
+	return find(err, func(child error) bool {
+		if matched {
+			return errors.As(child, scratch)
+		}
+		matched = errors.As(child, target)
+		return matched
+	})
+}
+
+func find(err error, match func(error) bool) []string {
+	tree, isTree := GetTree(err)
+	if !isTree {
+		return nil
+	}
+
+	var keys []string
+	for _, leaf := range sortedLeaves(tree) {
+		if match(leaf.err) {
+			keys = append(keys, leaf.Key())
+		}
+	}
+
+	return keys
+}
+
 // Flatten returns the error tree in flattened form.
 //
 // Each error inside the complete tree is stored under its full key.
 // The full key is constructed from the each error's path inside the tree
-// and joined together with the tree's delimiter.
+// and joined together with the tree's delimiter, as done by Path.String.
+// Node-level errors are stored under the full key of their tree, the node-level
+// error of the provided tree under the empty key. An error stored under the empty
+// key of the provided tree is stored under a single backslash instead, see Path.String.
 func Flatten(err error) map[string]error {
 	tree, isTree := GetTree(err)
 	if !isTree {
 		return nil
 	}
 
-	return flatten(tree, tree.getDelimiter(), nil)
+	return flatten(tree)
+}
+
+// SkipTree is used as a return value from a WalkFunc to indicate that the errors
+// of the tree named in the call are to be skipped.
+var SkipTree = errors.New("skip this tree")
+
+// WalkFunc is the type of the function called by Walk for each error in a tree.
+//
+// The path argument contains the path of the error inside the tree, err is the
+// error itself, which may be a *Tree.
+// If the function returns SkipTree when invoked on a *Tree, Walk skips the errors
+// of that tree. Any other non-nil error stops Walk, which then returns that error.
+type WalkFunc func(path Path, err error) error
+
+// Walk walks a given tree, calling fn for each error in the tree, including
+// nested trees.
+//
+// Errors are visited depth-first, the errors of each tree are visited in the
+// order configured for the provided tree, see Ordering and Less.
+// Each nested tree is visited before its errors.
+// If the provided error is not a *Tree, fn is never called.
+func Walk(err error, fn WalkFunc) error {
+	tree, isTree := GetTree(err)
+	if !isTree {
+		return nil
+	}
+
+	return walkFunc(tree, tree, fn, nil, nil)
 }
 
-func flatten(tree *Tree, delimiter string, visited []*Tree, keyPrefix ...string) map[string]error {
-	for _, visitedTree := range visited {
-		if tree == visitedTree {
-			return map[string]error{}
+func walkFunc(root *Tree, tree *Tree, fn WalkFunc, visited []*Tree, path Path) error {
+	visited = append(visited, tree)
+
+	for _, key := range root.orderedKeys(tree, path) {
+		child := tree.Errors[key]
+		childPath := path.Child(key)
+
+		childTree, isTree := GetTree(child)
+		if !isTree {
+			if err := fn(childPath, child); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Skip recursive references
+		if isVisited(childTree, visited) {
+			continue
+		}
+
+		if err := fn(childPath, childTree); err == SkipTree {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := walkFunc(root, childTree, fn, visited, childPath); err != nil {
+			return err
 		}
 	}
+
+	return nil
+}
+
+// Unflatten rebuilds an error tree from its flattened form, as returned by Flatten.
+//
+// Each key is split into its path using ParsePath and the given delimiter,
+// nested trees are created as needed. If delimiter is empty, DefaultDelimiter is used.
+// The returned tree uses the given delimiter.
+//
+// If the map holds errors for both a key and keys below that key, the error
+// is stored as the node-level error of the corresponding tree.
+// The error stored under the empty key is stored as node-level error of the
+// returned tree.
+func Unflatten(errorMap map[string]error, delimiter string) *Tree {
+	tree := New()
+	if delimiter != "" {
+		tree.Delimiter = delimiter
+	}
+
+	// Process keys in sorted order to get reproducible results
+	for _, key := range sortedKeys(errorMap) {
+		path := ParsePath(key, tree.Delimiter)
+		if path == nil {
+			tree.NodeError = errorMap[key]
+			continue
+		}
+		set(tree, path, errorMap[key], modeSet)
+	}
+
+	return tree
+}
+
+func flatten(tree *Tree) map[string]error {
+	leaves := walk(tree, tree, nil, nil)
+	errorMap := make(map[string]error, len(leaves))
+	for _, leaf := range leaves {
+		errorMap[leaf.Key()] = leaf.err
+	}
+
+	return errorMap
+}
+
+// flattenOrdered returns the flattened errors of a tree along with the
+// flattened keys, ordered according to the tree's Ordering and comparator.
+func flattenOrdered(tree *Tree) ([]string, map[string]error) {
+	leaves := sortedLeaves(tree)
+	keys := make([]string, len(leaves))
+	errorMap := make(map[string]error, len(leaves))
+	for i, leaf := range leaves {
+		keys[i] = leaf.Key()
+		errorMap[keys[i]] = leaf.err
+	}
+
+	return keys, errorMap
+}
+
+// walk collects all non-tree errors inside a tree, wrapped in a *PathError
+// carrying their path inside the tree.
+//
+// The delimiter and the order of the errors on every level are determined by
+// the root tree's delimiter and Ordering. The returned errors are not sorted any further.
+func walk(root *Tree, tree *Tree, visited []*Tree, path Path) []*PathError {
+	if isVisited(tree, visited) {
+		return nil
+	}
 	visited = append(visited, tree)
 
-	errors := tree.getErrors()
-	errorMap := make(map[string]error, len(errors))
+	var leaves []*PathError
+	if tree.NodeError != nil {
+		leaves = append(leaves, &PathError{
+			path:      path,
+			delimiter: root.getDelimiter(),
+			err:       tree.NodeError,
+		})
+	}
+
+	for _, key := range root.baseOrderedKeys(tree) {
+		err := tree.Errors[key]
+		childPath := path.Child(key)
 
-	for key, err := range errors {
 		if childTree, isTree := GetTree(err); isTree {
-			childPrefix := append(keyPrefix, key)
-			for childKey, childErr := range flatten(childTree, delimiter, visited, childPrefix...) {
-				errorMap[key+delimiter+childKey] = childErr
-			}
+			leaves = append(leaves, walk(root, childTree, visited, childPath)...)
 		} else {
-			errorMap[key] = err
+			leaves = append(leaves, &PathError{
+				path:      childPath,
+				delimiter: root.getDelimiter(),
+				err:       err,
+			})
 		}
 	}
 
-	return errorMap
+	return leaves
 }
@@ -2,6 +2,7 @@ package errortree
 
 import (
 	"errors"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -212,7 +213,7 @@ func TestSet(t *testing.T) {
 	require.EqualValues(t, tree.Errors, map[string]error{
 		"a": errors.New("test"),
 	})
-	require.NotNil(t, tree.Formatter)
+	require.Nil(t, tree.Formatter)
 	require.EqualValues(t, DefaultDelimiter, tree.Delimiter)
 
 	// Set with nil error: should be a no-op
@@ -239,16 +240,17 @@ func TestSet(t *testing.T) {
 	require.IsType(t, &Tree{}, nested)
 	require.EqualError(t, nested.(*Tree).Errors["a"], "test3")
 
-	// Set on non-tree: should panic
-	func() {
-		defer func() {
-			r := recover()
-			require.NotNil(t, r)
-			require.EqualValues(t, r, "Cannot set error: not an *errortree.Tree.")
-		}()
+	// Set on non-tree: should wrap the error as node-level error
+	parent := errors.New("test")
+	tree4 := Set(parent, "a", errors.New("test0")).(*Tree)
+	require.Equal(t, parent, tree4.NodeError)
+	require.EqualValues(t, map[string]error{
+		"":  errors.New("test"),
+		"a": errors.New("test0"),
+	}, Flatten(tree4))
 
-		Set(errors.New("test"), "a", errors.New("test0"))
-	}()
+	// Set nil error on non-tree: should return the error as it is
+	require.Equal(t, parent, Set(parent, "a", nil))
 }
 
 func TestAdd(t *testing.T) {
@@ -258,7 +260,7 @@ func TestAdd(t *testing.T) {
 	require.EqualValues(t, tree.Errors, map[string]error{
 		"a": errors.New("test"),
 	})
-	require.NotNil(t, tree.Formatter)
+	require.Nil(t, tree.Formatter)
 	require.EqualValues(t, DefaultDelimiter, tree.Delimiter)
 
 	// Add from existing tree
@@ -279,16 +281,10 @@ func TestAdd(t *testing.T) {
 	require.IsType(t, &Tree{}, nested)
 	require.EqualError(t, nested.(*Tree).Errors["a"], "test3")
 
-	// Add on non-tree: should panic
-	func() {
-		defer func() {
-			r := recover()
-			require.NotNil(t, r)
-			require.EqualValues(t, r, "Cannot set error: not an *errortree.Tree.")
-		}()
-
-		Set(errors.New("test"), "a", errors.New("test0"))
-	}()
+	// Add on non-tree: should wrap the error as node-level error
+	tree4 := Add(errors.New("test"), "a", errors.New("test0")).(*Tree)
+	require.EqualError(t, tree4.NodeError, "test")
+	require.EqualError(t, Get(tree4, "a"), "test0")
 
 	// Add on tree with existing key: should panic
 	func() {
@@ -301,3 +297,533 @@ func TestAdd(t *testing.T) {
 		Add(tree3, "a", errors.New("test0"))
 	}()
 }
+
+func TestTrySet(t *testing.T) {
+	// Set from nil
+	tree, err := TrySet(nil, "a", errors.New("test0"))
+	require.NoError(t, err)
+	require.EqualValues(t, map[string]error{"a": errors.New("test0")}, tree.Errors)
+
+	// nil error on nil parent: should return nil
+	tree2, err := TrySet(nil, "a", nil)
+	require.NoError(t, err)
+	require.Nil(t, tree2)
+
+	// Replace existing error
+	tree2, err = TrySetPath(tree, Path{"b", "c"}, errors.New("test1"))
+	require.NoError(t, err)
+	require.Equal(t, tree, tree2)
+	tree2, err = TrySet(tree, "a", errors.New("test2"))
+	require.NoError(t, err)
+	require.EqualError(t, Get(tree2, "a"), "test2")
+
+	// Set on non-tree: should wrap the error, even without an error to set
+	tree2, err = TrySet(errors.New("test"), "a", nil)
+	require.NoError(t, err)
+	require.EqualError(t, tree2.NodeError, "test")
+	require.Empty(t, tree2.Errors)
+
+	// Empty path
+	_, err = TrySetPath(tree, nil, errors.New("test"))
+	require.Equal(t, ErrEmptyPath, err)
+
+	// The tree is left untouched
+	require.EqualValues(t, []string{"a", "b:c"}, Keys(tree))
+}
+
+func TestTryAdd(t *testing.T) {
+	tree, err := TryAdd(nil, "a", errors.New("test0"))
+	require.NoError(t, err)
+	tree2, err := TryAddPath(tree, Path{"b", "c"}, errors.New("test1"))
+	require.NoError(t, err)
+	require.Equal(t, tree, tree2)
+
+	// Existing key
+	tree2, err = TryAdd(tree, "a", errors.New("test2"))
+	require.Nil(t, tree2)
+	require.True(t, errors.Is(err, ErrKeyExists))
+	require.EqualValues(t, "a: key exists", err.Error())
+
+	tree2, err = TryAddPath(tree, Path{"b", "c"}, errors.New("test2"))
+	require.Nil(t, tree2)
+	require.True(t, errors.Is(err, ErrKeyExists))
+	require.EqualValues(t, "b:c: key exists", err.Error())
+
+	require.EqualValues(t, map[string]error{
+		"a":   errors.New("test0"),
+		"b:c": errors.New("test1"),
+	}, Flatten(tree))
+}
+
+func TestSetPath_strict(t *testing.T) {
+	// The panicking variants keep reporting errors by panicking
+	tree := Set(nil, "a", errors.New("test0"))
+	SetPath(tree, Path{"b", "c"}, errors.New("test1"))
+
+	require.PanicsWithValue(t, "Cannot add error: key b:c exists.", func() {
+		AddPath(tree, Path{"b", "c"}, errors.New("test"))
+	})
+	require.PanicsWithValue(t, "Cannot set error: empty path.", func() {
+		AddPath(tree, Path{}, errors.New("test"))
+	})
+}
+
+func TestAppend(t *testing.T) {
+	// Append from nil
+	tree := Append(nil, "a", errors.New("test0")).(*Tree)
+	require.EqualValues(t, map[string]error{
+		"a": errors.New("test0"),
+	}, tree.Errors)
+
+	// nil error: should return parent
+	require.Equal(t, tree, Append(tree, "a", nil))
+
+	// Append to existing key
+	Append(tree, "a", errors.New("test1"))
+	require.EqualValues(t, MultiError{errors.New("test0"), errors.New("test1")}, Get(tree, "a"))
+	Append(tree, "a", MultiError{errors.New("test2"), errors.New("test3")})
+	require.EqualValues(t, MultiError{
+		errors.New("test0"),
+		errors.New("test1"),
+		errors.New("test2"),
+		errors.New("test3"),
+	}, Get(tree, "a"))
+	require.EqualValues(t, "4 errors occurred:\n\n* a: test0\n* a: test1\n* a: test2\n* a: test3", tree.Error())
+
+	// Append nested, to the node-level error of existing trees
+	AppendPath(tree, Path{"b", "c"}, errors.New("test4"))
+	AppendPath(tree, Path{"b"}, errors.New("test5"))
+	AppendPath(tree, Path{"b"}, errors.New("test6"))
+	require.EqualError(t, Get(tree, "b", "c"), "test4")
+	require.EqualValues(t, MultiError{errors.New("test5"), errors.New("test6")}, tree.Errors["b"].(*Tree).NodeError)
+
+	// Append on non-tree: should wrap the error as node-level error
+	tree = Append(errors.New("test7"), "a", errors.New("test8")).(*Tree)
+	require.EqualError(t, tree.NodeError, "test7")
+	require.EqualError(t, Get(tree, "a"), "test8")
+}
+
+func TestFind(t *testing.T) {
+	sentinel := errors.New("sentinel")
+
+	// A non-Tree error should return nil
+	require.Nil(t, Find(sentinel, sentinel))
+
+	tree := &Tree{
+		Errors: map[string]error{
+			"a": sentinel,
+			"b": errors.New("test0"),
+			"c": &Tree{
+				Errors: map[string]error{
+					"a": errors.New("test1"),
+					"b": sentinel,
+				},
+			},
+		},
+	}
+
+	require.EqualValues(t, []string{"a", "c:b"}, Find(tree, sentinel))
+	require.Nil(t, Find(tree, errors.New("sentinel")))
+}
+
+func TestFindAs(t *testing.T) {
+	first := &testError{msg: "first"}
+
+	tree := &Tree{
+		Errors: map[string]error{
+			"a": errors.New("test0"),
+			"b": first,
+			"c": &Tree{
+				Errors: map[string]error{
+					"a": &testError{msg: "second"},
+				},
+			},
+		},
+	}
+
+	// target is set to the first match
+	var target *testError
+	require.EqualValues(t, []string{"b", "c:a"}, FindAs(tree, &target))
+	require.Equal(t, first, target)
+
+	// target is not touched if there is no match
+	var addrErr *net.AddrError
+	require.Nil(t, FindAs(tree, &addrErr))
+	require.Nil(t, addrErr)
+
+	// A non-pointer target should panic
+	require.Panics(t, func() {
+		FindAs(tree, target)
+	})
+}
+
+func TestLookup(t *testing.T) {
+	// Non-tree lookup should return nil
+	require.Nil(t, Lookup(errors.New("test"), "test"))
+
+	tree := &Tree{
+		Delimiter: ".",
+		Errors: map[string]error{
+			"a": errors.New("test0"),
+			"c": &Tree{
+				Errors: map[string]error{
+					"a": errors.New("test1"),
+				},
+			},
+		},
+	}
+
+	// Non-existing keys
+	require.Nil(t, Lookup(tree, "b"))
+	require.Nil(t, Lookup(tree, "c", "b"))
+
+	// Top-level: existing key
+	pathErr := Lookup(tree, "a")
+	require.NotNil(t, pathErr)
+	require.EqualValues(t, []string{"a"}, pathErr.Path())
+	require.EqualError(t, pathErr.Unwrap(), "test0")
+
+	// Nested: existing key, using the top-level delimiter
+	pathErr = Lookup(tree, "c", "a")
+	require.NotNil(t, pathErr)
+	require.EqualValues(t, []string{"c", "a"}, pathErr.Path())
+	require.EqualValues(t, "c.a", pathErr.Key())
+	require.EqualError(t, pathErr, "c.a: test1")
+}
+
+func TestLeaves(t *testing.T) {
+	// Non-tree should return nil
+	require.Nil(t, Leaves(errors.New("test")))
+
+	tree := &Tree{
+		Delimiter: ".",
+		Errors: map[string]error{
+			"b": errors.New("test0"),
+			"a": &Tree{
+				Delimiter: "/",
+				Errors: map[string]error{
+					"b": errors.New("test1"),
+					"a": errors.New("test2"),
+				},
+			},
+		},
+	}
+	tree.Errors["c"] = tree
+
+	leaves := Leaves(tree)
+	require.Len(t, leaves, 3)
+	require.EqualValues(t, []string{"a", "a"}, leaves[0].Path())
+	require.EqualError(t, leaves[0], "a.a: test2")
+	require.EqualValues(t, []string{"a", "b"}, leaves[1].Path())
+	require.EqualError(t, leaves[1], "a.b: test1")
+	require.EqualValues(t, []string{"b"}, leaves[2].Path())
+	require.EqualError(t, leaves[2], "b: test0")
+}
+
+func TestSetPath(t *testing.T) {
+	// Set from nil, creating intermediate trees
+	tree := SetPath(nil, Path{"a", "b"}, errors.New("test0")).(*Tree)
+	require.NotNil(t, tree)
+	require.Len(t, tree.Errors, 1)
+	require.EqualError(t, Get(tree, "a", "b"), "test0")
+
+	// Set with nil error: should be a no-op without creating intermediate trees
+	require.Nil(t, SetPath(nil, Path{"a", "b"}, nil))
+	tree = SetPath(tree, Path{"c", "d"}, nil).(*Tree)
+	require.Len(t, tree.Errors, 1)
+
+	// Set into existing intermediate tree
+	tree.Delimiter = "."
+	tree2 := SetPath(tree, Path{"a", "c"}, errors.New("test1")).(*Tree)
+	require.Equal(t, tree, tree2)
+	require.EqualValues(t, []string{"a.b", "a.c"}, Keys(tree))
+
+	// Replace existing error
+	SetPath(tree, Path{"a", "c"}, errors.New("test2"))
+	require.EqualError(t, Get(tree, "a", "c"), "test2")
+
+	// Intermediate trees inherit the parent's settings
+	SetPath(tree, Path{"d", "e"}, errors.New("test3"))
+	require.EqualValues(t, ".", tree.Errors["d"].(*Tree).Delimiter)
+
+	// Empty path: should panic
+	require.PanicsWithValue(t, "Cannot set error: empty path.", func() {
+		SetPath(tree, Path{}, errors.New("test"))
+	})
+
+	// Non-tree on the path: should be converted into a tree
+	SetPath(tree, Path{"a", "b", "c"}, errors.New("test4"))
+	subTree, isTree := GetTree(Get(tree, "a", "b"))
+	require.True(t, isTree)
+	require.EqualError(t, subTree.NodeError, "test0")
+	require.EqualError(t, Get(tree, "a", "b", "c"), "test4")
+
+	// Set on non-tree: should wrap the error as node-level error
+	tree = SetPath(errors.New("test"), Path{"a", "b"}, errors.New("test5")).(*Tree)
+	require.EqualValues(t, []string{"", "a:b"}, Keys(tree))
+}
+
+func TestSetNodeError(t *testing.T) {
+	// nil error: should return parent
+	require.Nil(t, SetNodeError(nil, Path{"a"}, nil))
+
+	// Set on nil parent: should return new tree
+	tree, isTree := GetTree(SetNodeError(nil, nil, errors.New("test0")))
+	require.True(t, isTree)
+	require.EqualError(t, tree.NodeError, "test0")
+
+	// Set on nested tree, creating intermediate trees
+	SetPath(tree, Path{"a", "b"}, errors.New("test1"))
+	SetNodeError(tree, Path{"a"}, errors.New("test2"))
+	require.EqualError(t, tree.Errors["a"].(*Tree).NodeError, "test2")
+	require.EqualError(t, Get(tree, "a", "b"), "test1")
+
+	// Errors on the path are converted into node-level errors
+	SetNodeError(tree, Path{"a", "b", "c"}, errors.New("test3"))
+	require.EqualError(t, GetPath(tree, Path{"a", "b"}).(*Tree).NodeError, "test1")
+	require.EqualError(t, GetPath(tree, Path{"a", "b", "c"}).(*Tree).NodeError, "test3")
+
+	// Node-level errors are reported like any other error
+	require.EqualValues(t, map[string]error{
+		"":      errors.New("test0"),
+		"a":     errors.New("test2"),
+		"a:b":   errors.New("test1"),
+		"a:b:c": errors.New("test3"),
+	}, Flatten(tree))
+	require.EqualValues(t, []string{"", "a", "a:b", "a:b:c"}, Keys(tree))
+	require.EqualValues(t, "4 errors occurred:\n\n* test0\n* a: test2\n* a:b: test1\n* a:b:c: test3", tree.Error())
+
+	leaves := Leaves(tree)
+	require.Len(t, leaves, 4)
+	require.EqualValues(t, Path{"a"}, leaves[1].Path())
+	require.EqualValues(t, "a: test2", leaves[1].Error())
+	require.EqualValues(t, "test0", leaves[0].Error())
+
+	// Set on non-tree: should wrap the error
+	tree = SetNodeError(errors.New("test4"), Path{"a"}, errors.New("test5")).(*Tree)
+	require.EqualValues(t, map[string]error{
+		"":  errors.New("test4"),
+		"a": errors.New("test5"),
+	}, Flatten(tree))
+}
+
+func TestAddPath(t *testing.T) {
+	// Add from nil, creating intermediate trees
+	tree := AddPath(nil, Path{"a", "b"}, errors.New("test0")).(*Tree)
+	require.NotNil(t, tree)
+	require.EqualError(t, Get(tree, "a", "b"), "test0")
+
+	// Add into existing intermediate tree
+	AddPath(tree, Path{"a", "c"}, errors.New("test1"))
+	require.EqualValues(t, []string{"a:b", "a:c"}, Keys(tree))
+
+	// Add with existing path: should panic, reporting the full key
+	require.PanicsWithValue(t, "Cannot add error: key a:b exists.", func() {
+		AddPath(tree, Path{"a", "b"}, errors.New("test"))
+	})
+}
+
+func TestGetPath(t *testing.T) {
+	tree := &Tree{
+		Errors: map[string]error{
+			"a": errors.New("test0"),
+			"c": &Tree{
+				Errors: map[string]error{
+					"a": errors.New("test1"),
+				},
+			},
+		},
+	}
+
+	require.Nil(t, GetPath(tree, nil))
+	require.Nil(t, GetPath(tree, Path{"b"}))
+	require.EqualError(t, GetPath(tree, Path{"a"}), "test0")
+	require.EqualError(t, GetPath(tree, Path{"c", "a"}), "test1")
+	require.Nil(t, GetPath(tree, Path{"c", "b"}))
+}
+
+func TestGetAnyPath(t *testing.T) {
+	tree := &Tree{
+		Errors: map[string]error{
+			"a": errors.New("test0"),
+			"c": &Tree{
+				Errors: map[string]error{
+					"a": errors.New("test1"),
+				},
+			},
+		},
+	}
+
+	require.Equal(t, tree, GetAnyPath(tree, nil))
+	require.Nil(t, GetAnyPath(tree, Path{"b"}))
+	require.EqualError(t, GetAnyPath(tree, Path{"c", "a"}), "test1")
+	require.Equal(t, tree.Errors["c"], GetAnyPath(tree, Path{"c", "b"}))
+	require.EqualError(t, GetAnyPath(tree, Path{"a", "b"}), "test0")
+}
+
+func TestFlatten_escaping(t *testing.T) {
+	tree := &Tree{
+		Errors: map[string]error{
+			"host:port": errors.New("test0"),
+			`a\b`:       errors.New("test1"),
+			"c": &Tree{
+				Errors: map[string]error{
+					"[::1]": errors.New("test2"),
+				},
+			},
+		},
+	}
+
+	expected := map[string]error{
+		`host\:port`: errors.New("test0"),
+		`a\\b`:       errors.New("test1"),
+		`c:[\:\:1]`:  errors.New("test2"),
+	}
+	require.EqualValues(t, expected, Flatten(tree))
+
+	// Every key can be split into its original path
+	for _, key := range Keys(tree) {
+		require.EqualValues(t, expected[key], GetPath(tree, ParsePath(key, tree.Delimiter)))
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	// Empty map
+	tree := Unflatten(nil, "")
+	require.NotNil(t, tree)
+	require.Nil(t, tree.ErrorOrNil())
+	require.EqualValues(t, DefaultDelimiter, tree.Delimiter)
+
+	// Round trip through Flatten
+	original := &Tree{
+		Delimiter: ".",
+		NodeError: errors.New("test1"),
+		Errors: map[string]error{
+			"a":   errors.New("test0"),
+			"":    errors.New("test5"),
+			"b.c": errors.New("test2"),
+			"d": &Tree{
+				Errors: map[string]error{
+					"a": errors.New("test3"),
+					"b": &Tree{
+						Errors: map[string]error{
+							"a": errors.New("test4"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tree = Unflatten(Flatten(original), ".")
+	require.EqualValues(t, ".", tree.Delimiter)
+	require.EqualValues(t, Flatten(original), Flatten(tree))
+	require.EqualError(t, tree.NodeError, "test1")
+	require.EqualError(t, Get(tree, "b.c"), "test2")
+	require.EqualError(t, Get(tree, "d", "b", "a"), "test4")
+	// The empty key is kept apart from the node-level error
+	require.EqualError(t, Get(tree, ""), "test5")
+
+	// nil errors are skipped
+	tree = Unflatten(map[string]error{"a:b": nil, "c": errors.New("test")}, "")
+	require.EqualValues(t, []string{"c"}, Keys(tree))
+
+	// Keys with errors below them become node-level errors
+	tree = Unflatten(map[string]error{"a": errors.New("test0"), "a:b": errors.New("test1")}, "")
+	subTree, isTree := GetTree(Get(tree, "a"))
+	require.True(t, isTree)
+	require.EqualError(t, subTree.NodeError, "test0")
+	require.EqualError(t, Get(tree, "a", "b"), "test1")
+	require.EqualValues(t, []string{"a", "a:b"}, Keys(tree))
+}
+
+func TestWalk(t *testing.T) {
+	fn := func(path Path, err error) error {
+		panic("must not be called")
+	}
+	require.NoError(t, Walk(errors.New("test"), fn))
+
+	tree := &Tree{
+		Errors: map[string]error{
+			"c": errors.New("test0"),
+			"a": &Tree{
+				Errors: map[string]error{
+					"b": errors.New("test1"),
+					"a": errors.New("test2"),
+				},
+			},
+			"b": &Tree{
+				Errors: map[string]error{
+					"a": errors.New("test3"),
+				},
+			},
+		},
+	}
+	tree.Errors["d"] = tree
+
+	type visit struct {
+		path   Path
+		isTree bool
+	}
+	var visited []visit
+	fn = func(path Path, err error) error {
+		_, isTree := GetTree(err)
+		visited = append(visited, visit{path, isTree})
+		return nil
+	}
+	require.NoError(t, Walk(tree, fn))
+	require.EqualValues(t, []visit{
+		{Path{"a"}, true},
+		{Path{"a", "a"}, false},
+		{Path{"a", "b"}, false},
+		{Path{"b"}, true},
+		{Path{"b", "a"}, false},
+		{Path{"c"}, false},
+	}, visited)
+
+	// SkipTree skips the errors of a tree
+	visited = nil
+	require.NoError(t, Walk(tree, func(path Path, err error) error {
+		fn(path, err)
+		if _, isTree := GetTree(err); isTree {
+			return SkipTree
+		}
+		return nil
+	}))
+	require.EqualValues(t, []visit{
+		{Path{"a"}, true},
+		{Path{"b"}, true},
+		{Path{"c"}, false},
+	}, visited)
+
+	// Other errors stop the walk
+	visited = nil
+	stop := errors.New("stop")
+	require.Equal(t, stop, Walk(tree, func(path Path, err error) error {
+		fn(path, err)
+		if len(path) == 2 {
+			return stop
+		}
+		return nil
+	}))
+	require.Len(t, visited, 2)
+}
+
+func TestGetPointer(t *testing.T) {
+	tree := &Tree{
+		Errors: map[string]error{
+			"a/b": errors.New("test0"),
+			"c": &Tree{
+				Errors: map[string]error{
+					"a": errors.New("test1"),
+				},
+			},
+		},
+	}
+
+	require.EqualError(t, GetPointer(tree, "/a~1b"), "test0")
+	require.EqualError(t, GetPointer(tree, "/c/a"), "test1")
+	require.Nil(t, GetPointer(tree, "/c/b"))
+	require.Nil(t, GetPointer(tree, ""))
+	require.Nil(t, GetPointer(tree, "c/a"))
+}
@@ -107,6 +107,12 @@ func Example() {
 		fmt.Println("[3] Config OK")
 	}
 
+	// Missing options can be detected using errors.Is, no matter where they are located in the tree
+	c = Configuration{}
+	if err := c.Validate(); errors.Is(err, ErrOptionMissing) {
+		fmt.Println("[4] Configuration option missing")
+	}
+
 	// Output: [0] 3 errors occurred:
 	//
 	// * Network:ListenAddress: Configuration option is missing
@@ -120,4 +126,5 @@ func Example() {
 	//
 	// * Storage:DataDirectory: Not a directory
 	// [3] Config OK
+	// [4] Configuration option missing
 }
@@ -1,6 +1,8 @@
 package errortree_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -77,6 +79,32 @@ func ExampleSet_duplicate() {
 	// * test: key re-used
 }
 
+func ExampleSet_nonTree() {
+	// An error returned by a helper, which is not a tree
+	err := errors.New("invalid configuration")
+
+	// The error is kept as the node-level error of the created tree
+	err = errortree.Set(err, "Debug", errors.New("invalid"))
+	fmt.Println(err.Error())
+	// Output: 2 errors occurred:
+	//
+	// * invalid configuration
+	// * Debug: invalid
+}
+
+func ExampleSetPath() {
+	var err error
+
+	// Intermediate trees are created automatically
+	err = errortree.SetPath(err, errortree.Path{"Network", "ListenAddress"}, errors.New("missing"))
+	err = errortree.SetPath(err, errortree.Path{"Servers"}.Index(0).Child("Name"), errors.New("invalid"))
+	fmt.Println(err.Error())
+	// Output: 2 errors occurred:
+	//
+	// * Network:ListenAddress: missing
+	// * Servers:0:Name: invalid
+}
+
 func ExampleFlatten() {
 	tree := &errortree.Tree{
 		Errors: map[string]error{
@@ -100,6 +128,18 @@ func ExampleFlatten() {
 	// key: b:c, value: nested
 }
 
+func ExampleUnflatten() {
+	// A flattened error map, for example received from another process
+	flattened := map[string]error{
+		"Network:ListenAddress": errors.New("missing"),
+		"Storage:DataDirectory": errors.New("not a directory"),
+	}
+
+	tree := errortree.Unflatten(flattened, errortree.DefaultDelimiter)
+	fmt.Println(errortree.Get(tree, "Network", "ListenAddress"))
+	// Output: missing
+}
+
 func ExampleKeys() {
 	tree := &errortree.Tree{
 		Errors: map[string]error{
@@ -117,6 +157,26 @@ func ExampleKeys() {
 	// Output: a, b:c, test
 }
 
+func ExampleFind() {
+	errMissing := errors.New("missing")
+
+	tree := &errortree.Tree{
+		Errors: map[string]error{
+			"a": errMissing,
+			"b": &errortree.Tree{
+				Errors: map[string]error{
+					"c": errors.New("nested"),
+					"d": errMissing,
+				},
+			},
+		},
+	}
+
+	// Find returns the keys of all errors matching the target
+	fmt.Println(strings.Join(errortree.Find(tree, errMissing), ", "))
+	// Output: a, b:d
+}
+
 func ExampleGet() {
 	tree := &errortree.Tree{
 		Errors: map[string]error{
@@ -194,3 +254,104 @@ func ExampleGetAny_nested() {
 	//
 	// * c: nested
 }
+
+func ExampleTree_MarshalJSON() {
+	errMissing := errors.New("missing")
+	errortree.RegisterError("missing", errMissing)
+
+	err := errortree.SetPath(nil, errortree.Path{"Network", "ListenAddress"}, errMissing)
+	encoded, _ := json.Marshal(err)
+	fmt.Println(string(encoded))
+
+	// Registered errors can be matched using errors.Is after decoding
+	decoded := errortree.New()
+	_ = json.Unmarshal(encoded, decoded)
+	fmt.Println(errors.Is(errortree.Get(decoded, "Network", "ListenAddress"), errMissing))
+	// Output: {"errors":{"Network":{"errors":{"ListenAddress":{"message":"missing","code":"missing"}}}}}
+	// true
+}
+
+func ExampleNewJSONFormatter() {
+	tree := errortree.SetPath(nil, errortree.Path{"Network", "ListenAddress"}, errors.New("missing")).(*errortree.Tree)
+	errortree.SetPath(tree, errortree.Path{"Network", "MaxClients"}, errors.New("Must be at least 1"))
+
+	tree.Formatter = errortree.NewJSONFormatter(errortree.JSONFormatterOptions{
+		Nested: true,
+		Indent: "  ",
+	})
+	fmt.Println(tree.Error())
+	// Output: {
+	//   "Network": {
+	//     "ListenAddress": "missing",
+	//     "MaxClients": "Must be at least 1"
+	//   }
+	// }
+}
+
+func ExampleTreeFormatter() {
+	tree := errortree.SetPath(nil, errortree.Path{"Network", "ListenAddress"}, errors.New("missing")).(*errortree.Tree)
+	errortree.SetPath(tree, errortree.Path{"Network", "MaxClients"}, errors.New("Must be at least 1"))
+	errortree.SetPath(tree, errortree.Path{"Storage", "DataDirectory"}, errors.New("missing"))
+
+	// A TreeFormatter has access to the structure of the tree, which allows rendering subtrees as headings
+	tree.TreeFormatter = func(tree *errortree.Tree) string {
+		var lines []string
+		errortree.Walk(tree, func(path errortree.Path, err error) error {
+			indent := strings.Repeat("  ", len(path)-1)
+			if _, isTree := errortree.GetTree(err); isTree {
+				lines = append(lines, indent+path[len(path)-1]+":")
+			} else {
+				lines = append(lines, indent+path[len(path)-1]+" - "+err.Error())
+			}
+			return nil
+		})
+		return strings.Join(lines, "\n")
+	}
+	fmt.Println(tree.Error())
+	// Output: Network:
+	//   ListenAddress - missing
+	//   MaxClients - Must be at least 1
+	// Storage:
+	//   DataDirectory - missing
+}
+
+func ExampleIndentedFormatter() {
+	tree := errortree.SetPath(nil, errortree.Path{"Network", "ListenAddress"}, errors.New("missing")).(*errortree.Tree)
+	errortree.SetPath(tree, errortree.Path{"Network", "MaxClients"}, errors.New("Must be at least 1"))
+	errortree.SetPath(tree, errortree.Path{"Storage", "DataDirectory"}, errors.New("missing"))
+
+	tree.TreeFormatter = errortree.IndentedFormatter
+	fmt.Println(tree.Error())
+	// Output: 3 errors occurred:
+	//
+	// ├── Network (2 errors)
+	// │   ├── ListenAddress: missing
+	// │   └── MaxClients: Must be at least 1
+	// └── Storage (1 error)
+	//     └── DataDirectory: missing
+}
+
+func ExampleGroup() {
+	group := errortree.NewGroup(context.Background())
+	group.SetLimit(2)
+
+	group.Go("Network", func(ctx context.Context) error {
+		return errors.New("unreachable")
+	})
+	group.Go("Storage", func(ctx context.Context) error {
+		nested := errortree.NewGroup(ctx)
+		nested.Go("DataDirectory", func(ctx context.Context) error {
+			return errors.New("missing")
+		})
+		nested.Go("Cache", func(ctx context.Context) error {
+			return nil
+		})
+		return nested.Wait()
+	})
+
+	fmt.Println(group.Wait())
+	// Output: 2 errors occurred:
+	//
+	// * Network: unreachable
+	// * Storage:DataDirectory: missing
+}
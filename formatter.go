@@ -2,7 +2,6 @@ package errortree
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 )
 
@@ -10,28 +9,133 @@ import (
 // This function can expected that the provided map contains a flattened map of all Errors
 type Formatter func(map[string]error) string
 
+// TreeFormatter defines a formatter which has access to the structure of a tree.
+//
+// In contrast to a Formatter, which only receives the flattened errors,
+// a TreeFormatter receives the tree itself. Walk may be used for visiting
+// all errors in the tree.
+type TreeFormatter func(tree *Tree) string
+
+// OrderedFormatter defines a formatter which, like a Formatter, receives the
+// flattened errors of a tree and additionally receives the flattened keys in the
+// order in which the errors should be reported.
+type OrderedFormatter func(keys []string, errorMap map[string]error) string
+
+// TreeFormatter returns a TreeFormatter which invokes the formatter with the
+// flattened errors of a tree, along with the keys as returned by Keys.
+//
+// This allows formatting flattened errors according to the tree's Ordering and comparator.
+func (f OrderedFormatter) TreeFormatter() TreeFormatter {
+	return func(tree *Tree) string {
+		keys, errorMap := flattenOrdered(tree)
+		return f(keys, errorMap)
+	}
+}
+
 // SimpleFormatter provides a simple Formatter which returns a message indicating
 // how many Errors occurred and details for every error.
-// The reported Errors are sorted alphabetically by key.
+// The reported Errors are sorted by key in natural order, as the map does not carry
+// the tree's ordering. SimpleTreeFormatter, which Error uses unless a formatter is
+// configured for the tree, reports the errors in the order of the keys returned by Keys.
+// Keys are reported as they are found in the map, which for flattened trees
+// means delimiters inside of keys are escaped.
+// Each error of a MultiError is reported separately under its key.
 func SimpleFormatter(errorMap map[string]error) string {
-	wrappedErrors := make([]string, len(errorMap))
-	pluralSuffix := ""
-	if len(errorMap) != 1 {
-		pluralSuffix = "s"
+	keys := sortedKeys(errorMap)
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		errs[i] = errorMap[key]
 	}
 
-	// Sort the keys
-	keys := make([]string, 0, len(errorMap))
-	for key := range errorMap {
-		keys = append(keys, key)
+	return formatSimple(keys, errs, 0)
+}
+
+// SimpleFormatterOptions holds the options for a formatter created by NewSimpleTreeFormatter.
+type SimpleFormatterOptions struct {
+	// BracketIndices specifies whether index keys (see IsIndex) are rendered in brackets,
+	// like Path.IndexedString does.
+	BracketIndices bool
+}
+
+// SimpleTreeFormatter provides a TreeFormatter which formats errors like
+// SimpleFormatter does, but reports them in the order configured for the tree.
+// Errors which have been dropped due to the tree's Limit are summarized in a final line.
+func SimpleTreeFormatter(tree *Tree) string {
+	return NewSimpleTreeFormatter(SimpleFormatterOptions{})(tree)
+}
+
+// NewSimpleTreeFormatter returns a TreeFormatter which formats errors like
+// SimpleTreeFormatter does, according to the provided options.
+func NewSimpleTreeFormatter(options SimpleFormatterOptions) TreeFormatter {
+	return func(tree *Tree) string {
+		leaves := sortedLeaves(tree)
+		keys := make([]string, len(leaves))
+		errs := make([]error, len(leaves))
+		for i, leaf := range leaves {
+			if options.BracketIndices {
+				keys[i] = indexedKey(leaf.Key(), leaf.path, leaf.delimiter)
+			} else {
+				keys[i] = leaf.Key()
+			}
+			errs[i] = leaf.err
+		}
+
+		return formatSimple(keys, errs, tree.Dropped())
 	}
-	sort.Strings(keys)
+}
 
-	// Construct the individual messages
+// indexedKey returns the flattened key of the error stored under the given path with
+// index keys rendered in brackets, like Path.IndexedString does.
+//
+// The key of a path consisting of a single empty key is retained, so it can be told
+// apart from the empty key of the top-level tree's node-level error.
+func indexedKey(key string, path Path, delimiter string) string {
+	if len(path) == 1 && path[0] == "" {
+		return key
+	}
+	return path.IndexedString(delimiter)
+}
+
+// formatSimple formats the given errors, each of which is reported under the key
+// with the same index.
+func formatSimple(keys []string, errs []error, dropped int) string {
+	wrappedErrors := make([]string, 0, len(keys))
+
+	// Construct the individual messages, reporting each error of a MultiError separately
 	for i, key := range keys {
-		wrappedErrors[i] = "* " + key + ": " + errorMap[key].Error()
+		for _, err := range splitErrors(errs[i]) {
+			if key == "" {
+				// Node-level error of the top-level tree
+				wrappedErrors = append(wrappedErrors, "* "+err.Error())
+			} else {
+				wrappedErrors = append(wrappedErrors, "* "+key+": "+err.Error())
+			}
+		}
 	}
 
-	return fmt.Sprintf("%d error%s occurred:\n\n%s", len(errorMap), pluralSuffix,
+	count := len(wrappedErrors)
+	if dropped > 0 {
+		wrappedErrors = append(wrappedErrors, summarizeDropped(dropped))
+	}
+
+	return fmt.Sprintf("%s occurred:\n\n%s", countErrors(count),
 		strings.Join(wrappedErrors, "\n"))
 }
+
+// countErrors returns a message stating the given number of errors.
+func countErrors(count int) string {
+	return fmt.Sprintf("%d %s", count, pluralize("error", count))
+}
+
+// summarizeDropped returns a message stating the given number of dropped errors.
+func summarizeDropped(dropped int) string {
+	return fmt.Sprintf("... and %d more %s", dropped, pluralize("error", dropped))
+}
+
+// pluralize returns the plural of the given word, unless count is one.
+func pluralize(word string, count int) string {
+	if count != 1 {
+		return word + "s"
+	}
+	return word
+}
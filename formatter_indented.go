@@ -0,0 +1,133 @@
+package errortree
+
+import (
+	"strings"
+)
+
+// IndentedFormatterOptions holds the options for a formatter created by NewIndentedFormatter.
+type IndentedFormatterOptions struct {
+	// ASCII specifies whether the tree is drawn using ASCII characters instead
+	// of Unicode box-drawing characters.
+	ASCII bool
+	// HideCounts specifies whether the number of errors inside of each nested
+	// tree is omitted.
+	HideCounts bool
+	// BracketIndices specifies whether index keys (see IsIndex) are rendered in brackets.
+	BracketIndices bool
+}
+
+// indentation holds the characters used for drawing a tree.
+type indentation struct {
+	child     string
+	lastChild string
+	line      string
+	empty     string
+}
+
+var (
+	unicodeIndentation = indentation{
+		child:     "├── ",
+		lastChild: "└── ",
+		line:      "│   ",
+		empty:     "    ",
+	}
+
+	asciiIndentation = indentation{
+		child:     "|-- ",
+		lastChild: "`-- ",
+		line:      "|   ",
+		empty:     "    ",
+	}
+)
+
+// IndentedFormatter provides a TreeFormatter which returns a message indicating
+// how many Errors occurred, followed by the tree drawn as a hierarchy using
+// Unicode box-drawing characters.
+//
+// Each nested tree is annotated with the number of errors it contains and,
+// if set, its node-level error. Each error of a MultiError is drawn separately.
+// The reported Errors are ordered according to the tree's Ordering and comparator
+// on every level. Errors which have been dropped due to the tree's Limit are
+// summarized in a final line.
+func IndentedFormatter(tree *Tree) string {
+	return NewIndentedFormatter(IndentedFormatterOptions{})(tree)
+}
+
+// NewIndentedFormatter returns a TreeFormatter which draws the tree as a hierarchy,
+// like IndentedFormatter does, according to the provided options.
+func NewIndentedFormatter(options IndentedFormatterOptions) TreeFormatter {
+	chars := unicodeIndentation
+	if options.ASCII {
+		chars = asciiIndentation
+	}
+
+	return func(tree *Tree) string {
+		lines, count := formatIndented(tree, tree, chars, options, nil, nil, "")
+		if tree.NodeError != nil {
+			lines = append([]string{tree.NodeError.Error()}, lines...)
+		}
+		if dropped := tree.Dropped(); dropped > 0 {
+			lines = append(lines, summarizeDropped(dropped))
+		}
+
+		return countErrors(count) + " occurred:\n\n" + strings.Join(lines, "\n")
+	}
+}
+
+// formatIndented returns the lines representing the errors of the given tree,
+// along with the number of errors inside the tree, including its node-level error.
+// The node-level error itself is not part of the returned lines.
+func formatIndented(root *Tree, tree *Tree, chars indentation, options IndentedFormatterOptions, visited []*Tree, path Path, prefix string) ([]string, int) {
+	visited = append(visited, tree)
+
+	// Skip recursive references and split errors of a MultiError up front,
+	// so the last child can be determined
+	var keys []string
+	var errs []error
+	for _, key := range root.orderedKeys(tree, path) {
+		if childTree, isTree := GetTree(tree.Errors[key]); isTree && isVisited(childTree, visited) {
+			continue
+		}
+		for _, err := range splitErrors(tree.Errors[key]) {
+			keys = append(keys, key)
+			errs = append(errs, err)
+		}
+	}
+
+	var lines []string
+	count := 0
+	if tree.NodeError != nil {
+		count += len(splitErrors(tree.NodeError))
+	}
+	for i, key := range keys {
+		branch, childPrefix := chars.child, prefix+chars.line
+		if i == len(keys)-1 {
+			branch, childPrefix = chars.lastChild, prefix+chars.empty
+		}
+
+		label := key
+		if options.BracketIndices && IsIndex(key) {
+			label = "[" + key + "]"
+		}
+
+		childTree, isTree := GetTree(errs[i])
+		if !isTree {
+			lines = append(lines, prefix+branch+label+": "+errs[i].Error())
+			count++
+			continue
+		}
+
+		childLines, childCount := formatIndented(root, childTree, chars, options, visited, path.Child(key), childPrefix)
+		heading := prefix + branch + label
+		if !options.HideCounts {
+			heading += " (" + countErrors(childCount) + ")"
+		}
+		if childTree.NodeError != nil {
+			heading += ": " + childTree.NodeError.Error()
+		}
+		lines = append(append(lines, heading), childLines...)
+		count += childCount
+	}
+
+	return lines, count
+}
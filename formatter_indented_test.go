@@ -0,0 +1,127 @@
+package errortree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newIndentedTestTree() *Tree {
+	tree := &Tree{
+		Errors: map[string]error{
+			"Network": &Tree{
+				Errors: map[string]error{
+					"ListenAddress": errors.New("missing"),
+					"MaxClients":    errors.New("Must be at least 1"),
+				},
+			},
+			"Storage": &Tree{
+				Errors: map[string]error{
+					"DataDirectory": errors.New("missing"),
+					"Cache": &Tree{
+						Errors: map[string]error{
+							"Size": errors.New("too small"),
+						},
+					},
+				},
+			},
+			"Debug": errors.New("invalid"),
+		},
+	}
+	tree.Errors["Recursive"] = tree
+
+	return tree
+}
+
+func TestIndentedFormatter(t *testing.T) {
+	require.EqualValues(t, `5 errors occurred:
+
+├── Debug: invalid
+├── Network (2 errors)
+│   ├── ListenAddress: missing
+│   └── MaxClients: Must be at least 1
+└── Storage (2 errors)
+    ├── Cache (1 error)
+    │   └── Size: too small
+    └── DataDirectory: missing`, IndentedFormatter(newIndentedTestTree()))
+
+	require.EqualValues(t, "0 errors occurred:\n\n", IndentedFormatter(&Tree{}))
+}
+
+func TestNewIndentedFormatter(t *testing.T) {
+	formatter := NewIndentedFormatter(IndentedFormatterOptions{
+		ASCII:      true,
+		HideCounts: true,
+	})
+	require.EqualValues(t, "5 errors occurred:\n\n"+
+		"|-- Debug: invalid\n"+
+		"|-- Network\n"+
+		"|   |-- ListenAddress: missing\n"+
+		"|   `-- MaxClients: Must be at least 1\n"+
+		"`-- Storage\n"+
+		"    |-- Cache\n"+
+		"    |   `-- Size: too small\n"+
+		"    `-- DataDirectory: missing", formatter(newIndentedTestTree()))
+}
+
+func TestNewIndentedFormatter_bracketIndices(t *testing.T) {
+	tree := SetPath(nil, Path{"Servers"}.Index(10).Child("Name"), errors.New("test0"))
+	tree = SetPath(tree, Path{"Servers"}.Index(2), errors.New("test1"))
+
+	formatter := NewIndentedFormatter(IndentedFormatterOptions{
+		BracketIndices: true,
+	})
+	require.EqualValues(t, `2 errors occurred:
+
+└── Servers (2 errors)
+    ├── [2]: test1
+    └── [10] (1 error)
+        └── Name: test0`, formatter(tree.(*Tree)))
+}
+
+func TestIndentedFormatter_insertionOrder(t *testing.T) {
+	tree := &Tree{Ordering: InsertionOrder}
+	Set(tree, "b", errors.New("test0"))
+	SetPath(tree, Path{"a", "b"}, errors.New("test1"))
+	SetPath(tree, Path{"a", "a"}, errors.New("test2"))
+
+	require.EqualValues(t, `3 errors occurred:
+
+├── b: test0
+└── a (2 errors)
+    ├── b: test1
+    └── a: test2`, IndentedFormatter(tree))
+}
+
+func TestIndentedFormatter_nodeErrors(t *testing.T) {
+	tree := newIndentedTestTree()
+	tree.NodeError = errors.New("invalid configuration")
+	SetNodeError(tree, Path{"Storage", "Cache"}, errors.New("invalid cache"))
+
+	require.EqualValues(t, `7 errors occurred:
+
+invalid configuration
+├── Debug: invalid
+├── Network (2 errors)
+│   ├── ListenAddress: missing
+│   └── MaxClients: Must be at least 1
+└── Storage (3 errors)
+    ├── Cache (2 errors): invalid cache
+    │   └── Size: too small
+    └── DataDirectory: missing`, IndentedFormatter(tree))
+}
+
+func TestIndentedFormatter_multiErrors(t *testing.T) {
+	var tree error
+	tree = Append(tree, "a", errors.New("test0"))
+	tree = Append(tree, "a", errors.New("test1"))
+	tree = AppendPath(tree, Path{"b", "c"}, errors.New("test2"))
+
+	require.EqualValues(t, `3 errors occurred:
+
+├── a: test0
+├── a: test1
+└── b (1 error)
+    └── c: test2`, IndentedFormatter(tree.(*Tree)))
+}
@@ -0,0 +1,219 @@
+package errortree
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONFormatterOptions holds the options for a JSON formatter created by NewJSONFormatter.
+type JSONFormatterOptions struct {
+	// Nested specifies whether errors are rendered as nested objects instead of
+	// a single object mapping the flattened keys to the errors.
+	// Node-level errors are rendered under NodeErrorKey of their object.
+	Nested bool
+	// NodeErrorKey specifies the key under which node-level errors are rendered in
+	// nested objects. If empty, node-level errors are rendered under the empty key,
+	// where they collide with errors stored under an empty key: the error reported
+	// later replaces the other one. Setting a key which is not used by the tree keeps them apart.
+	NodeErrorKey string
+	// Delimiter specifies the delimiter used for splitting flattened keys when
+	// rendering nested objects or bracketed indices. If empty, DefaultDelimiter is used.
+	Delimiter string
+	// BracketIndices specifies whether index keys are rendered in brackets, like
+	// Path.IndexedString does. This only applies to flat objects.
+	BracketIndices bool
+	// Prefix specifies the prefix for each line when the output is indented.
+	Prefix string
+	// Indent specifies the indentation of the output. If empty, the output is not indented.
+	Indent string
+	// RenderError is used for rendering each error to a value which can be encoded to JSON.
+	// If nil, each error is rendered as its message.
+	// The errors of a MultiError are rendered individually, as a list.
+	RenderError func(err error) interface{}
+	// DroppedKey specifies the key under which formatters returned by NewJSONTreeFormatter
+	// render the number of errors dropped due to the tree's Limit. The number is only
+	// rendered if errors have been dropped and no error is stored under the same key.
+	// If empty, "dropped" is used.
+	DroppedKey string
+}
+
+// JSONFormatter provides a Formatter which returns a JSON object mapping
+// every key to the message of the corresponding error.
+// The reported Errors are sorted by key in natural order. NewJSONTreeFormatter may be
+// used for reporting them in the order configured for a tree.
+func JSONFormatter(errorMap map[string]error) string {
+	return NewJSONFormatter(JSONFormatterOptions{})(errorMap)
+}
+
+// NewJSONFormatter returns a Formatter which renders errors as a JSON object
+// according to the provided options.
+//
+// The reported Errors are sorted by key in natural order. NewJSONTreeFormatter may be
+// used for reporting them in the order configured for a tree.
+func NewJSONFormatter(options JSONFormatterOptions) Formatter {
+	formatter := newOrderedJSONFormatter(options)
+
+	return func(errorMap map[string]error) string {
+		return formatter(sortedKeys(errorMap), errorMap, 0)
+	}
+}
+
+// NewJSONTreeFormatter returns a TreeFormatter which renders errors as a JSON object
+// according to the provided options, like a formatter returned by NewJSONFormatter.
+//
+// The reported Errors are ordered like the keys returned by Keys.
+// The Delimiter option is ignored, the tree's delimiter is used instead.
+// The number of errors dropped due to the tree's Limit is rendered under DroppedKey.
+func NewJSONTreeFormatter(options JSONFormatterOptions) TreeFormatter {
+	return func(tree *Tree) string {
+		treeOptions := options
+		treeOptions.Delimiter = tree.getDelimiter()
+		keys, errorMap := flattenOrdered(tree)
+		return newOrderedJSONFormatter(treeOptions)(keys, errorMap, tree.Dropped())
+	}
+}
+
+// newOrderedJSONFormatter returns a function rendering the errors as a JSON object
+// in the order of the given keys, along with the given number of dropped errors.
+func newOrderedJSONFormatter(options JSONFormatterOptions) func(keys []string, errorMap map[string]error, dropped int) string {
+	if options.Delimiter == "" {
+		options.Delimiter = DefaultDelimiter
+	}
+	if options.DroppedKey == "" {
+		options.DroppedKey = "dropped"
+	}
+	if options.RenderError == nil {
+		options.RenderError = func(err error) interface{} {
+			return err.Error()
+		}
+	}
+
+	return func(keys []string, errorMap map[string]error, dropped int) string {
+		root := newJSONObject()
+		for _, key := range keys {
+			rendered := renderJSONError(options.RenderError, errorMap[key])
+			path := ParsePath(key, options.Delimiter)
+
+			if options.Nested && path == nil {
+				// Node-level error of the top-level tree
+				root.set(options.NodeErrorKey, rendered)
+			} else if options.Nested {
+				root.setPath(path, rendered, options.NodeErrorKey)
+			} else if options.BracketIndices {
+				root.set(indexedKey(key, path, options.Delimiter), rendered)
+			} else {
+				root.set(key, rendered)
+			}
+		}
+		if _, exists := root.values[options.DroppedKey]; dropped > 0 && !exists {
+			root.set(options.DroppedKey, dropped)
+		}
+
+		encoded := root.encode()
+		if options.Indent == "" {
+			return string(encoded)
+		}
+
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, encoded, options.Prefix, options.Indent); err != nil {
+			return string(encoded)
+		}
+		return indented.String()
+	}
+}
+
+// renderJSONError renders an error using the given function.
+//
+// The errors of a MultiError are rendered individually and returned as a list.
+func renderJSONError(render func(err error) interface{}, err error) interface{} {
+	multiErr, isMulti := err.(MultiError)
+	if !isMulti {
+		return render(err)
+	}
+
+	rendered := make([]interface{}, len(multiErr))
+	for i, err := range multiErr {
+		rendered[i] = render(err)
+	}
+	return rendered
+}
+
+// jsonObject is a JSON object which retains the order of its keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{
+		values: make(map[string]interface{}),
+	}
+}
+
+func (o *jsonObject) set(key string, value interface{}) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// setPath stores a value under the given path, creating nested objects as needed.
+//
+// Values stored at the same path as a nested object, like node-level errors,
+// are stored inside the nested object under nodeKey.
+func (o *jsonObject) setPath(path Path, value interface{}, nodeKey string) {
+	existing, exists := o.values[path[0]]
+	child, isObject := existing.(*jsonObject)
+
+	if len(path) == 1 {
+		if isObject {
+			child.set(nodeKey, value)
+		} else {
+			o.set(path[0], value)
+		}
+		return
+	}
+
+	if !isObject {
+		child = newJSONObject()
+		if exists {
+			child.set(nodeKey, existing)
+		}
+		o.set(path[0], child)
+	}
+	child.setPath(path[1:], value, nodeKey)
+}
+
+func (o *jsonObject) encode() []byte {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.Write(encodeJSONValue(key))
+		buffer.WriteByte(':')
+		if child, isObject := o.values[key].(*jsonObject); isObject {
+			buffer.Write(child.encode())
+		} else {
+			buffer.Write(encodeJSONValue(o.values[key]))
+		}
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes()
+}
+
+// encodeJSONValue encodes a value to JSON without escaping HTML characters.
+//
+// Values which cannot be encoded are replaced by the message of the encoding error.
+func encodeJSONValue(value interface{}) []byte {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return encodeJSONValue(err.Error())
+	}
+
+	return bytes.TrimRight(buffer.Bytes(), "\n")
+}
@@ -0,0 +1,167 @@
+package errortree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	require.EqualValues(t, "{}", JSONFormatter(nil))
+
+	// Use error values that would break the correct order to ensure we are ordering by key and not by value
+	require.EqualValues(t, `{"a":"c","b:a":"a & <b>","c":"b"}`, JSONFormatter(
+		map[string]error{
+			"a":   errors.New("c"),
+			"b:a": errors.New("a & <b>"),
+			"c":   errors.New("b"),
+		},
+	))
+}
+
+func TestNewJSONFormatter(t *testing.T) {
+	errorMap := map[string]error{
+		"a":          errors.New("test0"),
+		"b.a":        errors.New("test1"),
+		`b.b\.c`:     errors.New("test2"),
+		"c.a.a":      errors.New("test3"),
+		"c.b":        errors.New("test4"),
+		"unrendered": errors.New("test5"),
+	}
+
+	// Nested output
+	formatter := NewJSONFormatter(JSONFormatterOptions{
+		Nested:    true,
+		Delimiter: ".",
+	})
+	require.EqualValues(t, `{"a":"test0","b":{"a":"test1","b.c":"test2"},"c":{"a":{"a":"test3"},"b":"test4"},"unrendered":"test5"}`,
+		formatter(errorMap))
+
+	// Indentation and custom rendering
+	formatter = NewJSONFormatter(JSONFormatterOptions{
+		Prefix: "#",
+		Indent: "  ",
+		RenderError: func(err error) interface{} {
+			if err.Error() == "test5" {
+				return func() {}
+			}
+			return map[string]string{"message": err.Error()}
+		},
+	})
+	require.EqualValues(t, `{
+#  "a": {
+#    "message": "test0"
+#  },
+#  "b.a": {
+#    "message": "test1"
+#  },
+#  "b.b\\.c": {
+#    "message": "test2"
+#  },
+#  "c.a.a": {
+#    "message": "test3"
+#  },
+#  "c.b": {
+#    "message": "test4"
+#  },
+#  "unrendered": "json: unsupported type: func()"
+#}`, formatter(errorMap))
+}
+
+func TestNewJSONFormatter_bracketIndices(t *testing.T) {
+	formatter := NewJSONFormatter(JSONFormatterOptions{
+		BracketIndices: true,
+	})
+	require.EqualValues(t, `{"Servers[2]:Name":"test0","Servers[10]:Name":"test1"}`, formatter(map[string]error{
+		"Servers:10:Name": errors.New("test1"),
+		"Servers:2:Name":  errors.New("test0"),
+	}))
+}
+
+func TestNewJSONTreeFormatter(t *testing.T) {
+	tree := &Tree{
+		Delimiter: ".",
+		Ordering:  InsertionOrder,
+	}
+	Set(tree, "b", errors.New("test0"))
+	SetPath(tree, Path{"a", "b"}, errors.New("test1"))
+	SetPath(tree, Path{"a", "a"}, errors.New("test2"))
+
+	formatter := NewJSONTreeFormatter(JSONFormatterOptions{})
+	require.EqualValues(t, `{"b":"test0","a.b":"test1","a.a":"test2"}`, formatter(tree))
+
+	formatter = NewJSONTreeFormatter(JSONFormatterOptions{Nested: true})
+	require.EqualValues(t, `{"b":"test0","a":{"b":"test1","a":"test2"}}`, formatter(tree))
+}
+
+func TestNewJSONTreeFormatter_dropped(t *testing.T) {
+	tree := &Tree{Limit: 1}
+	Set(tree, "a", errors.New("test0"))
+	Set(tree, "b", errors.New("test1"))
+	Set(tree, "c", errors.New("test2"))
+
+	formatter := NewJSONTreeFormatter(JSONFormatterOptions{})
+	require.EqualValues(t, `{"a":"test0","dropped":2}`, formatter(tree))
+
+	formatter = NewJSONTreeFormatter(JSONFormatterOptions{DroppedKey: "more"})
+	require.EqualValues(t, `{"a":"test0","more":2}`, formatter(tree))
+
+	// Errors take precedence over the number of dropped errors
+	formatter = NewJSONTreeFormatter(JSONFormatterOptions{DroppedKey: "a"})
+	require.EqualValues(t, `{"a":"test0"}`, formatter(tree))
+}
+
+func TestNewJSONFormatter_nodeErrors(t *testing.T) {
+	errorMap := map[string]error{
+		"":    errors.New("test0"),
+		"a":   errors.New("test1"),
+		"a:b": errors.New("test2"),
+	}
+
+	formatter := NewJSONFormatter(JSONFormatterOptions{})
+	require.EqualValues(t, `{"":"test0","a":"test1","a:b":"test2"}`, formatter(errorMap))
+
+	// Node-level errors are rendered under the empty key
+	formatter = NewJSONFormatter(JSONFormatterOptions{Nested: true})
+	require.EqualValues(t, `{"":"test0","a":{"":"test1","b":"test2"}}`, formatter(errorMap))
+
+	// The order of the keys does not matter
+	root := newJSONObject()
+	root.setPath(Path{"a", "b"}, "test2", "")
+	root.setPath(Path{"a"}, "test1", "")
+	require.EqualValues(t, `{"a":{"b":"test2","":"test1"}}`, string(root.encode()))
+}
+
+func TestNewJSONFormatter_emptyKey(t *testing.T) {
+	tree := New()
+	tree.NodeError = errors.New("test0")
+	Set(tree, "", errors.New("test1"))
+	SetPath(tree, Path{"Servers"}.Index(2), errors.New("test2"))
+	SetNodeError(tree, Path{"a"}, errors.New("test3"))
+	SetPath(tree, Path{"a", ""}, errors.New("test4"))
+
+	// Bracketed indices keep the node-level error under the empty key
+	formatter := NewJSONTreeFormatter(JSONFormatterOptions{BracketIndices: true})
+	require.EqualValues(t, `{"":"test0","Servers[2]":"test2","\\":"test1","a":"test3","a:":"test4"}`, formatter(tree))
+
+	// Node-level errors are kept apart from errors stored under an empty key using NodeErrorKey
+	formatter = NewJSONTreeFormatter(JSONFormatterOptions{Nested: true, NodeErrorKey: "error"})
+	require.EqualValues(t, `{"error":"test0","Servers":{"2":"test2"},"":"test1","a":{"error":"test3","":"test4"}}`, formatter(tree))
+}
+
+func TestNewJSONFormatter_multiErrors(t *testing.T) {
+	errorMap := map[string]error{
+		"a": MultiError{errors.New("test0"), errors.New("test1")},
+		"b": errors.New("test2"),
+	}
+
+	require.EqualValues(t, `{"a":["test0","test1"],"b":"test2"}`, JSONFormatter(errorMap))
+
+	formatter := NewJSONFormatter(JSONFormatterOptions{
+		RenderError: func(err error) interface{} {
+			return map[string]string{"message": err.Error()}
+		},
+	})
+	require.EqualValues(t, `{"a":[{"message":"test0"},{"message":"test1"}],"b":{"message":"test2"}}`, formatter(errorMap))
+}
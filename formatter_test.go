@@ -25,3 +25,64 @@ func TestSimpleFormatter(t *testing.T) {
 		},
 	))
 }
+
+func TestSimpleFormatter_naturalOrder(t *testing.T) {
+	require.EqualValues(t, "3 errors occurred:\n\n* Servers:1: a\n* Servers:2: b\n* Servers:10: c", SimpleFormatter(
+		map[string]error{
+			"Servers:10": errors.New("c"),
+			"Servers:2":  errors.New("b"),
+			"Servers:1":  errors.New("a"),
+		},
+	))
+}
+
+func TestSimpleFormatter_emptyKey(t *testing.T) {
+	tree := New()
+	tree.NodeError = errors.New("test0")
+	Set(tree, "", errors.New("test1"))
+
+	require.EqualValues(t, "2 errors occurred:\n\n* test0\n* \\: test1", SimpleFormatter(Flatten(tree)))
+	require.EqualValues(t, "2 errors occurred:\n\n* test0\n* \\: test1", tree.Error())
+}
+
+func TestSimpleTreeFormatter(t *testing.T) {
+	tree := New()
+	Set(tree, "b", errors.New("test0"))
+	SetPath(tree, Path{"a", "b"}, errors.New("test1"))
+	SetPath(tree, Path{"a", "a"}, errors.New("test2"))
+
+	require.EqualValues(t, "3 errors occurred:\n\n* a:a: test2\n* a:b: test1\n* b: test0", SimpleTreeFormatter(tree))
+
+	tree.Ordering = InsertionOrder
+	require.EqualValues(t, "3 errors occurred:\n\n* b: test0\n* a:b: test1\n* a:a: test2", SimpleTreeFormatter(tree))
+}
+
+func TestNewSimpleTreeFormatter(t *testing.T) {
+	tree := New()
+	tree.NodeError = errors.New("test0")
+	SetPath(tree, Path{"Servers"}.Index(2).Child("Name"), errors.New("test1"))
+	SetPath(tree, Path{"Servers", "a:b"}, errors.New("test2"))
+	SetPath(tree, Path{"Ports"}.Index(0), errors.New("test3"))
+
+	formatter := NewSimpleTreeFormatter(SimpleFormatterOptions{BracketIndices: true})
+	require.EqualValues(t, "4 errors occurred:\n\n* test0\n* Ports[0]: test3\n* Servers[2]:Name: test1\n* Servers:a\\:b: test2", formatter(tree))
+
+	formatter = NewSimpleTreeFormatter(SimpleFormatterOptions{})
+	require.EqualValues(t, SimpleTreeFormatter(tree), formatter(tree))
+	require.Contains(t, formatter(tree), "* Servers:2:Name: test1")
+}
+
+func TestOrderedFormatter_TreeFormatter(t *testing.T) {
+	tree := New()
+	tree.Ordering = InsertionOrder
+	Set(tree, "b", errors.New("test0"))
+	SetPath(tree, Path{"a", "b"}, errors.New("test1"))
+
+	formatter := OrderedFormatter(func(keys []string, errorMap map[string]error) string {
+		require.EqualValues(t, []string{"b", "a:b"}, keys)
+		require.EqualValues(t, Flatten(tree), errorMap)
+		return "formatter_called"
+	})
+	tree.TreeFormatter = formatter.TreeFormatter()
+	require.EqualValues(t, "formatter_called", tree.Error())
+}
@@ -0,0 +1,131 @@
+package errortree
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group runs functions concurrently and collects their errors in a tree,
+// storing each function's error under the key the function was started with.
+//
+// Nested trees are created by returning the result of a nested group's Wait
+// from a function.
+// A Group must be created using NewGroup and must not be reused after Wait returns.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	tree   *SyncTree
+	wg     sync.WaitGroup
+	sem    chan struct{}
+
+	mu       sync.Mutex
+	running  int
+	failFast bool
+	failed   bool
+}
+
+// NewGroup returns a new group, which passes a context derived from ctx to its functions.
+//
+// The derived context is canceled once Wait returns or, if fail-fast is enabled,
+// when the first function returns an error.
+func NewGroup(ctx context.Context) *Group {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+		tree:   NewSyncTree(nil),
+	}
+}
+
+// SetLimit limits the number of functions running concurrently to n.
+// A negative value or zero removes the limit.
+//
+// This function panics if it is called while functions are running.
+func (g *Group) SetLimit(n int) {
+	g.mu.Lock()
+	running := g.running
+	g.mu.Unlock()
+	if running != 0 {
+		panic("Cannot set limit: functions are running.")
+	}
+
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// SetFailFast specifies whether the group's context is canceled when the first
+// function returns an error.
+//
+// Once the context has been canceled this way, errors matching context.Canceled,
+// as reported by errors.Is, are not collected, as they are caused by the cancellation.
+func (g *Group) SetFailFast(failFast bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.failFast = failFast
+}
+
+// Go runs the given function in a new goroutine, storing its error under the given key.
+//
+// If a limit is set, Go blocks until the function can be run without exceeding the limit.
+// Errors of functions sharing a key are combined, like Append does.
+func (g *Group) Go(key string, fn func(ctx context.Context) error) {
+	// Release the semaphore the function acquired, even if the limit changes later on
+	sem := g.sem
+	if sem != nil {
+		sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	g.running++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			g.mu.Lock()
+			g.running--
+			g.mu.Unlock()
+		}()
+		if sem != nil {
+			defer func() { <-sem }()
+		}
+
+		if err := fn(g.ctx); err != nil && g.collect(err) {
+			g.tree.Append(key, err)
+		}
+	}()
+}
+
+// collect reports whether the given error should be collected, canceling the
+// group's context if fail-fast is enabled.
+func (g *Group) collect(err error) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.failed && errors.Is(err, context.Canceled) {
+		return false
+	}
+	if g.failFast && !g.failed {
+		g.failed = true
+		g.cancel()
+	}
+	return true
+}
+
+// Wait blocks until all functions have returned and returns the collected errors.
+//
+// The returned error is a *Tree holding the errors of all failed functions,
+// or nil if no function failed.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	return g.tree.ErrorOrNil()
+}
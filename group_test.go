@@ -0,0 +1,130 @@
+package errortree
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	// No functions: should return nil
+	require.Nil(t, NewGroup(context.Background()).Wait())
+
+	group := NewGroup(context.Background())
+	group.Go("a", func(ctx context.Context) error {
+		return errors.New("test0")
+	})
+	group.Go("b", func(ctx context.Context) error {
+		return nil
+	})
+	group.Go("c", func(ctx context.Context) error {
+		// Nested groups produce nested trees
+		nested := NewGroup(ctx)
+		nested.Go("d", func(ctx context.Context) error {
+			return errors.New("test1")
+		})
+		return nested.Wait()
+	})
+
+	err := group.Wait()
+	require.IsType(t, &Tree{}, err)
+	require.EqualValues(t, map[string]error{
+		"a":   errors.New("test0"),
+		"c:d": errors.New("test1"),
+	}, Flatten(err))
+
+	// The context is canceled once Wait returns
+	require.Error(t, group.ctx.Err())
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	group := NewGroup(context.Background())
+	group.SetLimit(2)
+
+	var running, maxRunning int32
+	for i := 0; i < 10; i++ {
+		group.Go(strconv.Itoa(i), func(ctx context.Context) error {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				previous := atomic.LoadInt32(&maxRunning)
+				if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return errors.New("test")
+		})
+	}
+
+	require.Len(t, Keys(group.Wait()), 10)
+	require.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+
+	// Changing the limit while functions are running: should panic
+	group = NewGroup(context.Background())
+	group.SetLimit(1)
+	release := make(chan struct{})
+	group.Go("a", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	require.PanicsWithValue(t, "Cannot set limit: functions are running.", func() {
+		group.SetLimit(2)
+	})
+	close(release)
+	require.Nil(t, group.Wait())
+
+	// Setting a limit while functions without a limit are running: should panic
+	group = NewGroup(context.Background())
+	release = make(chan struct{})
+	group.Go("a", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	require.PanicsWithValue(t, "Cannot set limit: functions are running.", func() {
+		group.SetLimit(1)
+	})
+	close(release)
+	require.Nil(t, group.Wait())
+}
+
+func TestGroup_SetFailFast(t *testing.T) {
+	group := NewGroup(context.Background())
+	group.SetFailFast(true)
+
+	started := make(chan struct{})
+	group.Go("a", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	group.Go("b", func(ctx context.Context) error {
+		<-started
+		return errors.New("test0")
+	})
+
+	// Errors caused by the cancellation are not collected
+	require.EqualValues(t, map[string]error{
+		"b": errors.New("test0"),
+	}, Flatten(group.Wait()))
+}
+
+func TestGroup_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	group := NewGroup(ctx)
+	group.Go("a", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	cancel()
+
+	// Without fail-fast, errors caused by canceling the parent context are collected
+	err := group.Wait()
+	require.True(t, errors.Is(err, context.Canceled))
+	require.EqualValues(t, []string{"a"}, Keys(err))
+}
@@ -0,0 +1,199 @@
+package errortree
+
+var _ error = (*ImmutableTree)(nil)
+
+// ImmutableTree is an error tree which cannot be modified.
+//
+// Functions modifying the tree return a new version of the tree, leaving the
+// original version untouched. Trees which are not affected by a modification are
+// shared between versions, so creating a new version only copies the trees on
+// the modified path.
+// The zero value is not usable, NewImmutableTree must be used for creating trees.
+type ImmutableTree struct {
+	tree *Tree
+}
+
+// NewImmutableTree returns an immutable copy of the given tree, including all nested trees.
+//
+// The settings of the given tree, like its delimiter, are retained.
+// If tree is nil, an empty tree is returned.
+func NewImmutableTree(tree *Tree) *ImmutableTree {
+	if tree == nil {
+		tree = New()
+	}
+
+	return &ImmutableTree{
+		tree: immutableError(tree).(*Tree),
+	}
+}
+
+// Tree returns a mutable copy of the tree, including all nested trees.
+func (t *ImmutableTree) Tree() *Tree {
+	return copyTree(t.tree, make(map[*Tree]*Tree))
+}
+
+// Set returns a new version of the tree, with the error under the given key
+// created or replaced, like Set does.
+func (t *ImmutableTree) Set(key string, err error) *ImmutableTree {
+	return t.SetPath(Path{key}, err)
+}
+
+// SetPath returns a new version of the tree, with the error under the given path
+// created or replaced, like SetPath does.
+func (t *ImmutableTree) SetPath(path Path, err error) *ImmutableTree {
+	err = immutableError(err)
+	return t.update(path, func(tree *Tree) {
+		SetPath(tree, path, err)
+	})
+}
+
+// Add returns a new version of the tree, with the error added under the given key,
+// like Add does.
+//
+// This function panics if the key is already present in the tree.
+func (t *ImmutableTree) Add(key string, err error) *ImmutableTree {
+	return t.AddPath(Path{key}, err)
+}
+
+// AddPath returns a new version of the tree, with the error added under the given path,
+// like AddPath does.
+//
+// This function panics if the path is already present in the tree.
+func (t *ImmutableTree) AddPath(path Path, err error) *ImmutableTree {
+	err = immutableError(err)
+	return t.update(path, func(tree *Tree) {
+		AddPath(tree, path, err)
+	})
+}
+
+// Append returns a new version of the tree, with the error appended to the errors
+// under the given key, like Append does.
+func (t *ImmutableTree) Append(key string, err error) *ImmutableTree {
+	return t.AppendPath(Path{key}, err)
+}
+
+// AppendPath returns a new version of the tree, with the error appended to the errors
+// under the given path, like AppendPath does.
+func (t *ImmutableTree) AppendPath(path Path, err error) *ImmutableTree {
+	err = immutableError(err)
+	return t.update(path, func(tree *Tree) {
+		AppendPath(tree, path, err)
+	})
+}
+
+// SetNodeError returns a new version of the tree, with the node-level error of the
+// tree under the given path set, like SetNodeError does.
+func (t *ImmutableTree) SetNodeError(path Path, err error) *ImmutableTree {
+	err = immutableError(err)
+	return t.update(path, func(tree *Tree) {
+		SetNodeError(tree, path, err)
+	})
+}
+
+// update returns a new version of the tree, modified by fn.
+//
+// The top-level tree and all trees on the given path are copied before fn is invoked,
+// so fn may modify them. All other trees are shared with the current version.
+func (t *ImmutableTree) update(path Path, fn func(tree *Tree)) *ImmutableTree {
+	root := shallowCopyTree(t.tree)
+
+	parent := root
+	for _, key := range path {
+		childTree, isTree := GetTree(parent.Errors[key])
+		if !isTree {
+			break
+		}
+
+		childTree = shallowCopyTree(childTree)
+		parent.Errors[key] = childTree
+		parent = childTree
+	}
+
+	fn(root)
+
+	return &ImmutableTree{
+		tree: root,
+	}
+}
+
+// immutableError prepares an error for being stored in an immutable tree.
+//
+// Trees are copied, so they cannot be modified through the original tree,
+// while the trees of an *ImmutableTree are shared.
+func immutableError(err error) error {
+	if immutableTree, isImmutable := err.(*ImmutableTree); isImmutable {
+		return immutableTree.tree
+	}
+	if tree, isTree := GetTree(err); isTree {
+		copied := copyTree(tree, make(map[*Tree]*Tree))
+		initTree(copied, false, nil)
+		return copied
+	}
+	return err
+}
+
+// shallowCopyTree returns a copy of the given tree, sharing the errors it holds.
+func shallowCopyTree(tree *Tree) *Tree {
+	copied := *tree
+	copied.Errors = make(map[string]error, len(tree.Errors))
+	for key, err := range tree.Errors {
+		copied.Errors[key] = err
+	}
+	copied.order = append([]string(nil), tree.order...)
+	copied.frozen = false
+
+	return &copied
+}
+
+// Get retrieves the error for the given key, like Get does.
+//
+// Nested trees are returned as *ImmutableTree.
+func (t *ImmutableTree) Get(key string, path ...string) error {
+	return t.GetPath(append(Path{key}, path...))
+}
+
+// GetPath retrieves the error for the given path, like GetPath does.
+//
+// Nested trees are returned as *ImmutableTree.
+func (t *ImmutableTree) GetPath(path Path) error {
+	err := GetPath(t.tree, path)
+	if childTree, isTree := GetTree(err); isTree {
+		return &ImmutableTree{
+			tree: childTree,
+		}
+	}
+	return err
+}
+
+// Flatten returns the flattened errors of the tree, like Flatten does.
+func (t *ImmutableTree) Flatten() map[string]error {
+	return Flatten(t.tree)
+}
+
+// Keys returns the keys of the tree, like Keys does.
+func (t *ImmutableTree) Keys() []string {
+	return Keys(t.tree)
+}
+
+// Dropped returns the number of errors which have been dropped due to the Limit
+// of the tree, like Tree.Dropped does.
+func (t *ImmutableTree) Dropped() int {
+	return t.tree.Dropped()
+}
+
+func (t *ImmutableTree) Error() string {
+	return t.tree.Error()
+}
+
+// ErrorOrNil returns nil if the tree is empty or the tree itself otherwise.
+func (t *ImmutableTree) ErrorOrNil() error {
+	if t.tree.ErrorOrNil() == nil {
+		return nil
+	}
+	return t
+}
+
+// Unwrap returns all errors contained in the tree, like Tree.Unwrap does.
+func (t *ImmutableTree) Unwrap() []error {
+	return t.tree.Unwrap()
+}
@@ -0,0 +1,78 @@
+package errortree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableTree(t *testing.T) {
+	empty := NewImmutableTree(nil)
+	require.Nil(t, empty.ErrorOrNil())
+
+	v1 := empty.Set("a", errors.New("test0"))
+	v2 := v1.SetPath(Path{"b", "c"}, errors.New("test1"))
+	v3 := v2.AppendPath(Path{"b", "c"}, errors.New("test2"))
+	v4 := v3.SetNodeError(Path{"b"}, errors.New("test3"))
+
+	// Previous versions are left untouched
+	require.Empty(t, empty.Keys())
+	require.EqualValues(t, []string{"a"}, v1.Keys())
+	require.EqualValues(t, []string{"a", "b:c"}, v2.Keys())
+	require.EqualValues(t, map[string]error{
+		"a":   errors.New("test0"),
+		"b:c": errors.New("test1"),
+	}, v2.Flatten())
+	require.EqualValues(t, MultiError{errors.New("test1"), errors.New("test2")}, v3.Get("b", "c"))
+	require.Nil(t, v3.Get("b").(*ImmutableTree).tree.NodeError)
+	require.EqualValues(t, "4 errors occurred:\n\n* a: test0\n* b: test3\n* b:c: test1\n* b:c: test2", v4.Error())
+
+	// Unmodified trees are shared between versions
+	v5 := v4.Set("d", errors.New("test4"))
+	require.Same(t, v4.tree.Errors["b"], v5.tree.Errors["b"])
+	require.NotSame(t, v4.tree, v5.tree)
+
+	// Add panics on existing keys, leaving the tree untouched
+	require.PanicsWithValue(t, "Cannot add error: key a exists.", func() {
+		v5.Add("a", errors.New("test"))
+	})
+	require.EqualError(t, v5.Get("a"), "test0")
+	require.EqualError(t, v5.Add("e", errors.New("test5")).Get("e"), "test5")
+
+	// Setting a nil error leaves the tree unchanged
+	require.EqualValues(t, v5.Flatten(), v5.Set("f", nil).Flatten())
+
+	// Matching errors
+	sentinel := errors.New("sentinel")
+	require.True(t, errors.Is(v5.Set("g", sentinel), sentinel))
+	require.False(t, errors.Is(v5, sentinel))
+}
+
+func TestImmutableTree_conversion(t *testing.T) {
+	tree := &Tree{Delimiter: "."}
+	SetPath(tree, Path{"a", "b"}, errors.New("test0"))
+
+	// Modifying the original tree does not affect the immutable tree
+	immutable := NewImmutableTree(tree)
+	Set(tree, "c", errors.New("test1"))
+	SetPath(tree, Path{"a", "d"}, errors.New("test2"))
+	require.EqualValues(t, []string{"a.b"}, immutable.Keys())
+
+	// Modifying a converted tree does not affect the immutable tree
+	converted := immutable.Tree()
+	require.EqualValues(t, ".", converted.Delimiter)
+	SetPath(converted, Path{"a", "c"}, errors.New("test3"))
+	require.EqualValues(t, []string{"a.b"}, immutable.Keys())
+
+	// Modifying trees stored in the immutable tree does not affect it
+	nested := Set(nil, "a", errors.New("test4")).(*Tree)
+	immutable = immutable.Set("e", nested)
+	Set(nested, "b", errors.New("test5"))
+	require.EqualValues(t, []string{"a.b", "e.a"}, immutable.Keys())
+
+	// Nested immutable trees are stored as trees
+	immutable = immutable.Set("f", NewImmutableTree(nested))
+	require.EqualValues(t, []string{"a.b", "e.a", "f.a", "f.b"}, immutable.Keys())
+	require.IsType(t, &ImmutableTree{}, immutable.Get("f"))
+}
@@ -0,0 +1,272 @@
+package errortree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+var (
+	_ json.Marshaler   = (*Tree)(nil)
+	_ json.Unmarshaler = (*Tree)(nil)
+)
+
+// registry holds the errors registered using RegisterError
+var registry struct {
+	sync.RWMutex
+	codes  []string
+	errors map[string]error
+}
+
+// RegisterError registers a sentinel error under the given code.
+//
+// When a tree is encoded to JSON, errors matching a registered error, as reported
+// by errors.Is, are encoded together with the code. When decoding a tree, errors
+// carrying a registered code are rehydrated, so errors.Is reports them as matching
+// the registered error again.
+//
+// Registering an error under an existing code replaces the previously registered error.
+// This function panics if code is empty or err is nil.
+func RegisterError(code string, err error) {
+	if code == "" || err == nil {
+		panic("Cannot register error: code and error must not be empty.")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if registry.errors == nil {
+		registry.errors = make(map[string]error)
+	}
+	if _, exists := registry.errors[code]; !exists {
+		registry.codes = append(registry.codes, code)
+	}
+	registry.errors[code] = err
+}
+
+// codeOf returns the code of the given error.
+//
+// If the error provides its own code by implementing a Code() string method,
+// that code is returned. Otherwise the code of the first registered error
+// matching the error is returned.
+func codeOf(err error) string {
+	var coder interface {
+		Code() string
+	}
+	if errors.As(err, &coder) {
+		return coder.Code()
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, code := range registry.codes {
+		if errors.Is(err, registry.errors[code]) {
+			return code
+		}
+	}
+
+	return ""
+}
+
+// decodeError creates an error from its message and code.
+//
+// If the code belongs to a registered error, either the registered error itself
+// or an error wrapping the registered error is returned.
+func decodeError(message string, code string) error {
+	if code == "" {
+		return errors.New(message)
+	}
+
+	registry.RLock()
+	registered := registry.errors[code]
+	registry.RUnlock()
+
+	if registered != nil && registered.Error() == message {
+		return registered
+	}
+
+	return &codedError{
+		message: message,
+		code:    code,
+		err:     registered,
+	}
+}
+
+// codedError is an error which has been decoded together with its code.
+type codedError struct {
+	message string
+	code    string
+	err     error
+}
+
+func (e *codedError) Error() string {
+	return e.message
+}
+
+// Code returns the error's code.
+func (e *codedError) Code() string {
+	return e.code
+}
+
+// Unwrap returns the registered error for the error's code, if any.
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// jsonTree represents the JSON encoding of a tree.
+type jsonTree struct {
+	Message string                 `json:"message,omitempty"`
+	Code    string                 `json:"code,omitempty"`
+	Errors  map[string]interface{} `json:"errors"`
+}
+
+// jsonLeaf represents the JSON encoding of an error which is not a tree.
+type jsonLeaf struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// jsonNode is used for decoding trees, errors which are not trees and lists of errors.
+//
+// Trees are told apart by the presence of the errors field, lists of errors
+// are decoded into the list field.
+type jsonNode struct {
+	Message string               `json:"message"`
+	Code    string               `json:"code"`
+	Errors  map[string]*jsonNode `json:"errors"`
+	List    []*jsonNode          `json:"-"`
+}
+
+// UnmarshalJSON decodes a node, which may either be an object or a list of objects.
+func (n *jsonNode) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(data, &n.List)
+	}
+
+	// Use a separate type for decoding the object, which does not implement json.Unmarshaler
+	type plainNode jsonNode
+	return json.Unmarshal(data, (*plainNode)(n))
+}
+
+// MarshalJSON encodes the tree to JSON.
+//
+// Each tree is encoded as an object holding its errors in the errors field.
+// Errors which are not trees are encoded as objects holding the error message
+// in the message field and the error's code (see RegisterError) in the code field.
+// The errors of a MultiError are encoded as a list of such objects.
+// The node-level error of a tree is encoded in the message and code fields of the tree.
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodeTree(t, nil))
+}
+
+func encodeTree(tree *Tree, visited []*Tree) *jsonTree {
+	visited = append(visited, tree)
+
+	encoded := &jsonTree{
+		Errors: make(map[string]interface{}, len(tree.Errors)),
+	}
+	if tree.NodeError != nil {
+		encoded.Message = tree.NodeError.Error()
+		encoded.Code = codeOf(tree.NodeError)
+	}
+
+	for key, err := range tree.Errors {
+		childTree, isTree := GetTree(err)
+		if multiErr, isMulti := err.(MultiError); isMulti {
+			leaves := make([]*jsonLeaf, len(multiErr))
+			for i, err := range multiErr {
+				leaves[i] = encodeLeaf(err)
+			}
+			encoded.Errors[key] = leaves
+			continue
+		}
+		if !isTree {
+			encoded.Errors[key] = encodeLeaf(err)
+			continue
+		}
+
+		// Skip recursive references
+		if isVisited(childTree, visited) {
+			continue
+		}
+		encoded.Errors[key] = encodeTree(childTree, visited)
+	}
+
+	return encoded
+}
+
+func encodeLeaf(err error) *jsonLeaf {
+	return &jsonLeaf{
+		Message: err.Error(),
+		Code:    codeOf(err),
+	}
+}
+
+// UnmarshalJSON decodes a tree from its JSON encoding, as created by MarshalJSON.
+//
+// Errors are decoded with their original message. If an error carries the code of
+// a registered error (see RegisterError), errors.Is reports it as matching the
+// registered error.
+// Any errors already present in the tree are replaced.
+// ErrFrozen is returned if the tree is frozen.
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	if t.frozen {
+		return ErrFrozen
+	}
+
+	var node jsonNode
+	if err := json.Unmarshal(data, &node); err != nil {
+		return err
+	}
+
+	t.Errors = nil
+	t.NodeError = nil
+	t.order = nil
+	t.dropped = 0
+	decodeTree(t, &node)
+	// Count the decoded errors towards the tree's limit
+	t.stored = errorCount(t)
+
+	return nil
+}
+
+func decodeTree(tree *Tree, node *jsonNode) {
+	tree.getErrors()
+	if node.Message != "" || node.Code != "" {
+		tree.NodeError = decodeError(node.Message, node.Code)
+	}
+
+	// Decode errors in sorted order to get a reproducible insertion order
+	keys := make([]string, 0, len(node.Errors))
+	for key := range node.Errors {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	for _, key := range keys {
+		child := node.Errors[key]
+		if child == nil {
+			continue
+		}
+
+		if child.List != nil {
+			var multiErr MultiError
+			for _, item := range child.List {
+				if item != nil {
+					multiErr = append(multiErr, decodeError(item.Message, item.Code))
+				}
+			}
+			if len(multiErr) > 0 {
+				tree.put(key, multiErr)
+			}
+		} else if child.Errors != nil {
+			childTree := newChild(tree)
+			decodeTree(childTree, child)
+			tree.put(key, childTree)
+		} else {
+			tree.put(key, decodeError(child.Message, child.Code))
+		}
+	}
+}
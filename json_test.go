@@ -0,0 +1,212 @@
+package errortree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type codeError struct{}
+
+func (e *codeError) Error() string {
+	return "custom"
+}
+
+func (e *codeError) Code() string {
+	return "custom_code"
+}
+
+func TestRegisterError(t *testing.T) {
+	sentinel := errors.New("json sentinel")
+	RegisterError("register_test", sentinel)
+	require.EqualValues(t, "register_test", codeOf(sentinel))
+	require.EqualValues(t, "register_test", codeOf(fmt.Errorf("wrapped: %w", sentinel)))
+	require.EqualValues(t, "", codeOf(errors.New("json sentinel")))
+
+	// Errors providing their own code take precedence
+	require.EqualValues(t, "custom_code", codeOf(&codeError{}))
+
+	// Re-registering replaces the error
+	replacement := errors.New("json sentinel replacement")
+	RegisterError("register_test", replacement)
+	require.EqualValues(t, "", codeOf(sentinel))
+	require.EqualValues(t, "register_test", codeOf(replacement))
+
+	require.Panics(t, func() {
+		RegisterError("", sentinel)
+	})
+	require.Panics(t, func() {
+		RegisterError("register_test", nil)
+	})
+}
+
+func TestTree_MarshalJSON(t *testing.T) {
+	sentinel := errors.New("missing")
+	RegisterError("marshal_missing", sentinel)
+
+	tree := &Tree{
+		Errors: map[string]error{
+			"a": errors.New("test0"),
+			"b": &Tree{
+				Errors: map[string]error{
+					"a": sentinel,
+					"b": &codeError{},
+				},
+			},
+			"c": &Tree{},
+		},
+	}
+	tree.Errors["d"] = tree
+
+	encoded, err := json.Marshal(tree)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"errors": {
+		"a": {"message": "test0"},
+		"b": {"errors": {
+			"a": {"message": "missing", "code": "marshal_missing"},
+			"b": {"message": "custom", "code": "custom_code"}
+		}},
+		"c": {"errors": {}}
+	}}`, string(encoded))
+}
+
+func TestTree_UnmarshalJSON(t *testing.T) {
+	sentinel := errors.New("missing")
+	RegisterError("unmarshal_missing", sentinel)
+
+	tree := &Tree{
+		Delimiter: ".",
+		Errors: map[string]error{
+			"existing": errors.New("existing"),
+		},
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"errors": {
+		"a": {"message": "test0"},
+		"b": {"errors": {
+			"a": {"message": "missing", "code": "unmarshal_missing"},
+			"b": {"message": "field: missing", "code": "unmarshal_missing"},
+			"c": {"message": "unknown", "code": "unknown_code"}
+		}},
+		"c": {"errors": {}},
+		"d": null
+	}}`), tree))
+
+	// Existing errors are replaced, settings are kept
+	require.EqualValues(t, []string{"a", "b.a", "b.b", "b.c"}, Keys(tree))
+	require.EqualValues(t, ".", tree.Errors["b"].(*Tree).Delimiter)
+	require.IsType(t, &Tree{}, tree.Errors["c"])
+
+	require.EqualError(t, Get(tree, "a"), "test0")
+
+	// Registered errors are rehydrated
+	require.Equal(t, sentinel, Get(tree, "b", "a"))
+	require.EqualError(t, Get(tree, "b", "b"), "field: missing")
+	require.True(t, errors.Is(Get(tree, "b", "b"), sentinel))
+
+	// Unknown codes are preserved
+	require.EqualError(t, Get(tree, "b", "c"), "unknown")
+	require.EqualValues(t, "unknown_code", codeOf(Get(tree, "b", "c")))
+
+	// Invalid JSON
+	require.Error(t, json.Unmarshal([]byte(`{"errors": []}`), tree))
+}
+
+func TestTree_JSON_roundTrip(t *testing.T) {
+	sentinel := errors.New("missing")
+	RegisterError("round_trip_missing", sentinel)
+
+	var tree error
+	tree = SetPath(tree, Path{"Network", "ListenAddress"}, sentinel)
+	tree = SetPath(tree, Path{"Network", "MaxClients"}, errors.New("Must be at least 1"))
+
+	encoded, err := json.Marshal(tree)
+	require.NoError(t, err)
+
+	decoded := New()
+	require.NoError(t, json.Unmarshal(encoded, decoded))
+	require.EqualValues(t, Flatten(tree), Flatten(decoded))
+	require.True(t, errors.Is(Get(decoded, "Network", "ListenAddress"), sentinel))
+}
+
+func TestTree_JSON_nodeErrors(t *testing.T) {
+	sentinel := errors.New("invalid")
+	RegisterError("node_invalid", sentinel)
+
+	tree := New()
+	tree.NodeError = errors.New("test0")
+	SetNodeError(tree, Path{"a"}, sentinel)
+	Set(tree, "b", New())
+	SetPath(tree, Path{"a", "b"}, errors.New("test1"))
+
+	encoded, err := json.Marshal(tree)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"message": "test0",
+		"errors": {
+			"a": {
+				"message": "invalid",
+				"code": "node_invalid",
+				"errors": {"b": {"message": "test1"}}
+			},
+			"b": {"errors": {}}
+		}
+	}`, string(encoded))
+
+	decoded := New()
+	require.NoError(t, json.Unmarshal(encoded, decoded))
+	require.EqualValues(t, Flatten(tree), Flatten(decoded))
+	require.True(t, errors.Is(decoded.Errors["a"].(*Tree).NodeError, sentinel))
+	require.Nil(t, decoded.Errors["b"].(*Tree).NodeError)
+}
+
+func TestTree_JSON_multiErrors(t *testing.T) {
+	sentinel := errors.New("too short")
+	RegisterError("multi_too_short", sentinel)
+
+	tree := Append(nil, "a", sentinel)
+	tree = Append(tree, "a", errors.New("invalid characters"))
+
+	encoded, err := json.Marshal(tree)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"errors": {
+			"a": [
+				{"message": "too short", "code": "multi_too_short"},
+				{"message": "invalid characters"}
+			]
+		}
+	}`, string(encoded))
+
+	decoded := New()
+	require.NoError(t, json.Unmarshal(encoded, decoded))
+	require.EqualValues(t, Flatten(tree), Flatten(decoded))
+	require.True(t, errors.Is(Get(decoded, "a"), sentinel))
+
+	// Empty lists are skipped
+	require.NoError(t, json.Unmarshal([]byte(`{"errors": {"a": [], "b": {"message": "test"}}}`), decoded))
+	require.EqualValues(t, []string{"b"}, Keys(decoded))
+}
+
+func TestTree_UnmarshalJSON_reset(t *testing.T) {
+	tree := &Tree{Ordering: InsertionOrder, Limit: 3}
+	Set(tree, "z", errors.New("test0"))
+	Set(tree, "a", errors.New("test1"))
+	Set(tree, "b", errors.New("test2"))
+	Set(tree, "c", errors.New("test3"))
+	require.EqualValues(t, 1, tree.Dropped())
+
+	// The insertion order and dropped errors of the previous errors are discarded
+	require.NoError(t, json.Unmarshal([]byte(`{"errors": {"a": {"message": "test4"}, "z": {"message": "test5"}}}`), tree))
+	require.EqualValues(t, []string{"a", "z"}, Keys(tree))
+	require.EqualValues(t, 0, tree.Dropped())
+	require.EqualValues(t, "2 errors occurred:\n\n* a: test4\n* z: test5", tree.Error())
+
+	// Decoded errors count towards the limit
+	Set(tree, "b", errors.New("test6"))
+	Set(tree, "c", errors.New("test7"))
+	require.EqualValues(t, []string{"a", "z", "b"}, Keys(tree))
+	require.EqualValues(t, 1, tree.Dropped())
+}
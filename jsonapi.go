@@ -0,0 +1,94 @@
+package errortree
+
+import (
+	"strconv"
+)
+
+// JSONAPIError represents an error object, as defined by the JSON:API specification.
+type JSONAPIError struct {
+	// Status holds the HTTP status code applicable to the error
+	Status string `json:"status,omitempty"`
+	// Code holds the code of the error, see RegisterError
+	Code string `json:"code,omitempty"`
+	// Title is a short, human-readable summary of the problem
+	Title string `json:"title,omitempty"`
+	// Detail holds the error message
+	Detail string `json:"detail,omitempty"`
+	// Source references the location of the error
+	Source *JSONAPIErrorSource `json:"source,omitempty"`
+}
+
+// JSONAPIErrorSource references the location of an error inside the request document.
+type JSONAPIErrorSource struct {
+	// Pointer holds a JSON Pointer, as defined by RFC 6901
+	Pointer string `json:"pointer,omitempty"`
+}
+
+// JSONAPIOptions holds the options for error objects created by NewJSONAPIErrors.
+type JSONAPIOptions struct {
+	// Status holds the HTTP status code applicable to each error.
+	// If zero, the status is omitted.
+	Status int
+	// Title is a short, human-readable summary of the problem, applied to each error
+	Title string
+	// PointerPrefix is prepended to each pointer, for example "/data/attributes"
+	PointerPrefix string
+	// MapKey is called for each key of an error's path before building the pointer,
+	// allowing keys to be renamed, for example from Go field names to JSON attribute names.
+	// If nil, keys are used as-is.
+	MapKey func(key string) string
+}
+
+// NewJSONAPIErrors returns a JSON:API error object for each error in the given tree.
+// The errors of a MultiError are reported as separate error objects.
+//
+// The pointer of each error object references the error's path inside the tree,
+// as modified by the provided options. The error objects are sorted in the same
+// way as the keys returned by Keys.
+// If the provided error is not a *Tree, a single error object without a source is returned.
+// If the provided error is nil, nil is returned.
+func NewJSONAPIErrors(err error, options JSONAPIOptions) []JSONAPIError {
+	if err == nil {
+		return nil
+	}
+
+	status := ""
+	if options.Status != 0 {
+		status = strconv.Itoa(options.Status)
+	}
+
+	tree, isTree := GetTree(err)
+	if !isTree {
+		return []JSONAPIError{{
+			Status: status,
+			Code:   codeOf(err),
+			Title:  options.Title,
+			Detail: err.Error(),
+		}}
+	}
+
+	leaves := sortedLeaves(tree)
+	apiErrors := make([]JSONAPIError, 0, len(leaves))
+	for _, leaf := range leaves {
+		path := leaf.Path()
+		if options.MapKey != nil {
+			for j, key := range path {
+				path[j] = options.MapKey(key)
+			}
+		}
+
+		for _, err := range splitErrors(leaf.err) {
+			apiErrors = append(apiErrors, JSONAPIError{
+				Status: status,
+				Code:   codeOf(err),
+				Title:  options.Title,
+				Detail: err.Error(),
+				Source: &JSONAPIErrorSource{
+					Pointer: options.PointerPrefix + path.Pointer(),
+				},
+			})
+		}
+	}
+
+	return apiErrors
+}
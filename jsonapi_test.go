@@ -0,0 +1,61 @@
+package errortree
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONAPIErrors(t *testing.T) {
+	sentinel := errors.New("missing")
+	RegisterError("jsonapi_missing", sentinel)
+
+	require.Nil(t, NewJSONAPIErrors(nil, JSONAPIOptions{}))
+
+	// Non-tree errors are reported without source
+	require.EqualValues(t, []JSONAPIError{{
+		Status: "500",
+		Code:   "jsonapi_missing",
+		Detail: "missing",
+	}}, NewJSONAPIErrors(sentinel, JSONAPIOptions{Status: 500}))
+
+	tree := SetPath(nil, Path{"Network", "ListenAddress"}, sentinel)
+	tree = SetPath(tree, Path{"Servers", "0", "a/b~c"}, errors.New("invalid"))
+
+	encoded, err := json.Marshal(NewJSONAPIErrors(tree, JSONAPIOptions{
+		Status:        422,
+		Title:         "Invalid attribute",
+		PointerPrefix: "/data/attributes",
+		MapKey:        strings.ToLower,
+	}))
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{
+			"status": "422",
+			"code": "jsonapi_missing",
+			"title": "Invalid attribute",
+			"detail": "missing",
+			"source": {"pointer": "/data/attributes/network/listenaddress"}
+		},
+		{
+			"status": "422",
+			"title": "Invalid attribute",
+			"detail": "invalid",
+			"source": {"pointer": "/data/attributes/servers/0/a~1b~0c"}
+		}
+	]`, string(encoded))
+}
+
+func TestNewJSONAPIErrors_multiErrors(t *testing.T) {
+	tree := Append(nil, "a", errors.New("test0"))
+	tree = Append(tree, "a", errors.New("test1"))
+
+	apiErrors := NewJSONAPIErrors(tree, JSONAPIOptions{})
+	require.Len(t, apiErrors, 2)
+	require.EqualValues(t, "test0", apiErrors[0].Detail)
+	require.EqualValues(t, "test1", apiErrors[1].Detail)
+	require.EqualValues(t, "/a", apiErrors[1].Source.Pointer)
+}
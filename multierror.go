@@ -0,0 +1,50 @@
+package errortree
+
+import (
+	"strings"
+)
+
+var _ error = MultiError(nil)
+
+// MultiError holds multiple errors stored under a single key of a tree,
+// as created by Append.
+type MultiError []error
+
+// Error returns the messages of all errors, separated by semicolons.
+func (e MultiError) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors, which allows errors.Is and errors.As
+// to match any of them.
+func (e MultiError) Unwrap() []error {
+	return e
+}
+
+// appendError combines an existing error with another error.
+//
+// If the existing error is nil, the other error is returned. Otherwise a MultiError
+// holding both errors is returned, with the errors of MultiError values being
+// merged instead of nested.
+func appendError(existing error, err error) error {
+	if existing == nil {
+		return err
+	}
+
+	var combined MultiError
+	combined = append(combined, splitErrors(existing)...)
+	return append(combined, splitErrors(err)...)
+}
+
+// splitErrors returns the errors held by a MultiError, or the error itself otherwise.
+func splitErrors(err error) []error {
+	if multiErr, isMulti := err.(MultiError); isMulti {
+		return multiErr
+	}
+	return []error{err}
+}
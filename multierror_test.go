@@ -0,0 +1,30 @@
+package errortree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiError(t *testing.T) {
+	sentinel := errors.New("test1")
+	err := MultiError{errors.New("test0"), sentinel}
+
+	require.EqualValues(t, "test0; test1", err.Error())
+	require.True(t, errors.Is(err, sentinel))
+
+	// Errors stored in a tree are matched as well
+	tree := Set(nil, "a", err)
+	require.True(t, errors.Is(tree, sentinel))
+	require.EqualValues(t, []string{"a"}, Find(tree, sentinel))
+}
+
+func TestAppendError(t *testing.T) {
+	err0, err1, err2 := errors.New("test0"), errors.New("test1"), errors.New("test2")
+
+	require.Equal(t, err0, appendError(nil, err0))
+	require.EqualValues(t, MultiError{err0, err1}, appendError(err0, err1))
+	require.EqualValues(t, MultiError{err0, err1, err2}, appendError(MultiError{err0, err1}, err2))
+	require.EqualValues(t, MultiError{err0, err1, err2}, appendError(err0, MultiError{err1, err2}))
+}
@@ -0,0 +1,260 @@
+package errortree
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidPointer indicates that a JSON Pointer could not be parsed.
+var ErrInvalidPointer = errors.New("invalid JSON pointer")
+
+// escapeCharacter is used for escaping delimiters inside of keys
+const escapeCharacter = '\\'
+
+// emptyKeyPath is the string representation of a path consisting of a single empty key.
+//
+// As the empty string represents the empty path, the path consisting of a single
+// empty key is represented by a single escape character, which cannot result
+// from escaping a key.
+const emptyKeyPath = string(escapeCharacter)
+
+// checkDelimiter returns the given delimiter, or DefaultDelimiter if it is empty.
+//
+// This function panics if the delimiter starts with the escape character, as keys
+// joined together using such a delimiter could not be split again.
+func checkDelimiter(delimiter string) string {
+	if delimiter == "" {
+		return DefaultDelimiter
+	}
+	if delimiter[0] == escapeCharacter {
+		panic("Cannot use delimiter: must not start with a backslash.")
+	}
+
+	return delimiter
+}
+
+// Path represents the location of an error inside a tree.
+//
+// Each element of a path is the key of an error in the tree on the
+// corresponding level.
+type Path []string
+
+// ParsePath parses a path which has been joined together using the given delimiter,
+// as done by Path.String.
+//
+// Escaped delimiters and escape characters inside the individual keys are unescaped.
+// If delimiter is empty, DefaultDelimiter is used.
+// This function panics if the delimiter starts with a backslash, which is used for escaping.
+// An empty string results in an empty path, while a single backslash results in
+// a path consisting of a single empty key.
+func ParsePath(s string, delimiter string) Path {
+	delimiter = checkDelimiter(delimiter)
+	if s == "" {
+		return nil
+	}
+	if s == emptyKeyPath {
+		return Path{""}
+	}
+
+	var path Path
+	var key strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] == escapeCharacter && i+1 < len(s) {
+			// Take the escaped character literally
+			_, size := utf8.DecodeRuneInString(s[i+1:])
+			key.WriteString(s[i+1 : i+1+size])
+			i += 1 + size
+		} else if strings.HasPrefix(s[i:], delimiter) {
+			path = append(path, key.String())
+			key.Reset()
+			i += len(delimiter)
+		} else {
+			key.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return append(path, key.String())
+}
+
+// EscapeKey escapes all occurrences of the first character of the delimiter
+// and the escape character (a backslash) inside of the given key.
+//
+// Escaping every character which could start a delimiter, instead of only complete
+// delimiters, guarantees that joined paths can be split into the original keys
+// again using ParsePath, even if a key ends with the start of the delimiter.
+// If delimiter is empty, DefaultDelimiter is used.
+// This function panics if the delimiter starts with a backslash, like ParsePath does.
+func EscapeKey(key string, delimiter string) string {
+	delimiter = checkDelimiter(delimiter)
+	start, _ := utf8.DecodeRuneInString(delimiter)
+
+	var escaped strings.Builder
+	for _, r := range key {
+		if r == start || r == escapeCharacter {
+			escaped.WriteByte(escapeCharacter)
+		}
+		escaped.WriteRune(r)
+	}
+
+	return escaped.String()
+}
+
+// Child returns a new path pointing to the child with the given key.
+func (p Path) Child(key string) Path {
+	child := make(Path, len(p)+1)
+	copy(child, p)
+	child[len(p)] = key
+
+	return child
+}
+
+// Index returns a new path pointing to the child with the given index.
+//
+// This is a convenience function for use with slices, the index is
+// converted to its decimal string representation.
+func (p Path) Index(index int) Path {
+	return p.Child(strconv.Itoa(index))
+}
+
+// Parent returns a new path pointing to the parent of the path.
+//
+// The parent of an empty path is the empty path.
+func (p Path) Parent() Path {
+	if len(p) == 0 {
+		return nil
+	}
+
+	parent := make(Path, len(p)-1)
+	copy(parent, p)
+
+	return parent
+}
+
+// String returns the path joined together using the given delimiter.
+//
+// Each key is escaped using EscapeKey, so the result can be parsed using ParsePath.
+// A path consisting of a single empty key is represented by a single backslash,
+// to tell it apart from the empty path.
+// If delimiter is empty, DefaultDelimiter is used.
+// This function panics if the delimiter starts with a backslash, like ParsePath does.
+func (p Path) String(delimiter string) string {
+	delimiter = checkDelimiter(delimiter)
+	if len(p) == 1 && p[0] == "" {
+		return emptyKeyPath
+	}
+
+	keys := make([]string, len(p))
+	for i, key := range p {
+		keys[i] = EscapeKey(key, delimiter)
+	}
+
+	return strings.Join(keys, delimiter)
+}
+
+var (
+	// jsonPointerEscaper escapes keys for use inside a JSON Pointer
+	jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+	// jsonPointerUnescaper unescapes keys inside a JSON Pointer
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// ParsePointer parses a JSON Pointer, as defined by RFC 6901, into a path.
+//
+// The empty pointer, which references the whole document, results in an empty path.
+// If the pointer is invalid, an error wrapping ErrInvalidPointer is returned.
+func ParsePointer(pointer string) (Path, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %q does not start with /", ErrInvalidPointer, pointer)
+	}
+
+	path := Path(strings.Split(pointer[1:], "/"))
+	for i, key := range path {
+		// A tilde must be followed by either 0 or 1
+		for j := 0; j < len(key); j++ {
+			if key[j] == '~' && (j+1 == len(key) || (key[j+1] != '0' && key[j+1] != '1')) {
+				return nil, fmt.Errorf("%w: %q contains an invalid escape sequence", ErrInvalidPointer, pointer)
+			}
+		}
+		path[i] = jsonPointerUnescaper.Replace(key)
+	}
+
+	return path, nil
+}
+
+var _ error = (*PathError)(nil)
+
+// IndexedString returns the path joined together using the given delimiter,
+// like String does, but renders index keys (see IsIndex) in brackets appended
+// to the preceding key, for example "Servers[2]:Name".
+//
+// The returned value is intended for display purposes and cannot be parsed using ParsePath.
+func (p Path) IndexedString(delimiter string) string {
+	delimiter = checkDelimiter(delimiter)
+
+	var joined strings.Builder
+	for i, key := range p {
+		if IsIndex(key) {
+			joined.WriteString("[" + key + "]")
+			continue
+		}
+		if i > 0 {
+			joined.WriteString(delimiter)
+		}
+		joined.WriteString(EscapeKey(key, delimiter))
+	}
+
+	return joined.String()
+}
+
+// Pointer returns the JSON Pointer, as defined by RFC 6901, referencing the path.
+//
+// The empty path results in the empty pointer.
+func (p Path) Pointer() string {
+	var pointer strings.Builder
+	for _, key := range p {
+		pointer.WriteByte('/')
+		pointer.WriteString(jsonPointerEscaper.Replace(key))
+	}
+
+	return pointer.String()
+}
+
+// PathError wraps an error stored inside a tree together with
+// the path under which the error is stored.
+type PathError struct {
+	path      Path
+	delimiter string
+	err       error
+}
+
+// Path returns the path of the wrapped error inside the tree.
+func (e *PathError) Path() Path {
+	path := make(Path, len(e.path))
+	copy(path, e.path)
+	return path
+}
+
+// Key returns the full key of the wrapped error, which is constructed
+// by joining the path together with the tree's delimiter, as done by Path.String.
+func (e *PathError) Key() string {
+	return e.path.String(e.delimiter)
+}
+
+// Unwrap returns the wrapped error.
+func (e *PathError) Unwrap() error {
+	return e.err
+}
+
+func (e *PathError) Error() string {
+	if len(e.path) == 0 {
+		return e.err.Error()
+	}
+	return e.Key() + ": " + e.err.Error()
+}
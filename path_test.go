@@ -0,0 +1,165 @@
+package errortree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathError(t *testing.T) {
+	err := errors.New("test")
+	pathErr := &PathError{
+		path:      []string{"a", "b"},
+		delimiter: ".",
+		err:       err,
+	}
+
+	require.EqualValues(t, []string{"a", "b"}, pathErr.Path())
+	require.EqualValues(t, "a.b", pathErr.Key())
+	require.EqualValues(t, "a.b: test", pathErr.Error())
+	require.Equal(t, err, pathErr.Unwrap())
+	require.True(t, errors.Is(pathErr, err))
+
+	// Modifying the returned path must not modify the error
+	pathErr.Path()[0] = "c"
+	require.EqualValues(t, "a.b", pathErr.Key())
+
+	// Node-level error of the top-level tree
+	pathErr = &PathError{delimiter: ".", err: err}
+	require.EqualValues(t, "", pathErr.Key())
+	require.EqualValues(t, "test", pathErr.Error())
+}
+
+func TestParsePath(t *testing.T) {
+	require.Nil(t, ParsePath("", ":"))
+	require.EqualValues(t, Path{"a"}, ParsePath("a", ":"))
+	require.EqualValues(t, Path{"a", "b", "c"}, ParsePath("a:b:c", ""))
+	require.EqualValues(t, Path{"a", "b:c"}, ParsePath("a.b:c", "."))
+	require.EqualValues(t, Path{""}, ParsePath(`\`, ":"))
+}
+
+func TestPath_Child(t *testing.T) {
+	parent := Path{"a"}
+	child := parent.Child("b")
+	require.EqualValues(t, Path{"a", "b"}, child)
+	require.EqualValues(t, Path{"a"}, parent)
+
+	// Children of the same parent must not share memory
+	sibling := child[:1].Child("c")
+	require.EqualValues(t, Path{"a", "b"}, child)
+	require.EqualValues(t, Path{"a", "c"}, sibling)
+
+	require.EqualValues(t, Path{"a"}, Path(nil).Child("a"))
+}
+
+func TestPath_Index(t *testing.T) {
+	require.EqualValues(t, Path{"a", "10"}, Path{"a"}.Index(10))
+}
+
+func TestPath_Parent(t *testing.T) {
+	require.EqualValues(t, Path{"a"}, Path{"a", "b"}.Parent())
+	require.EqualValues(t, Path{}, Path{"a"}.Parent())
+	require.Nil(t, Path{}.Parent())
+}
+
+func TestPath_String(t *testing.T) {
+	require.EqualValues(t, `a\:::b`, Path{"a:", "b"}.String("::"))
+	require.EqualValues(t, Path{"a:", "b"}, ParsePath(Path{"a:", "b"}.String("::"), "::"))
+	require.EqualValues(t, "", Path{}.String(":"))
+	require.EqualValues(t, `\`, Path{""}.String(":"))
+	require.EqualValues(t, "a", Path{"a"}.String(":"))
+	require.EqualValues(t, "a.b", Path{"a", "b"}.String("."))
+	require.EqualValues(t, "a:b", Path{"a", "b"}.String(""))
+}
+
+func TestEscapeKey(t *testing.T) {
+	require.EqualValues(t, "abc", EscapeKey("abc", ":"))
+	require.EqualValues(t, `host\:port`, EscapeKey("host:port", ""))
+	require.EqualValues(t, `a\\b`, EscapeKey(`a\b`, ":"))
+	require.EqualValues(t, `\:\:1`, EscapeKey("::1", ":"))
+	require.EqualValues(t, `a\:\:b\:c`, EscapeKey("a::b:c", "::"))
+	require.EqualValues(t, `a\.`, EscapeKey("a.", ".."))
+}
+
+func TestPath_roundTrip(t *testing.T) {
+	for _, delimiter := range []string{":", ".", "::", "..", "->", "ä"} {
+		for _, path := range []Path{
+			{""},
+			{"a"},
+			{"a", "b"},
+			{"host:port", "[::1]:80"},
+			{`C:\dir\`, `\`, `\:`},
+			{"a::", "b", "->", "ä.ä"},
+			{"", "a", ""},
+			{"a:", "b"},
+			{"a.", ".b.", "-"},
+			{":", "::", ":::"},
+		} {
+			joined := path.String(delimiter)
+			require.EqualValues(t, path, ParsePath(joined, delimiter), "delimiter %q, joined %q", delimiter, joined)
+		}
+	}
+}
+
+func TestPath_backslashDelimiter(t *testing.T) {
+	for _, delimiter := range []string{`\`, `\:`} {
+		require.PanicsWithValue(t, "Cannot use delimiter: must not start with a backslash.", func() {
+			Path{"a", "b"}.String(delimiter)
+		})
+		require.PanicsWithValue(t, "Cannot use delimiter: must not start with a backslash.", func() {
+			ParsePath(`a\b`, delimiter)
+		})
+		require.PanicsWithValue(t, "Cannot use delimiter: must not start with a backslash.", func() {
+			EscapeKey("a", delimiter)
+		})
+	}
+
+	// Delimiters may contain a backslash after their first character
+	require.EqualValues(t, Path{"a", "b"}, ParsePath(Path{"a", "b"}.String(`:\`), `:\`))
+}
+
+func TestPath_Pointer(t *testing.T) {
+	require.EqualValues(t, "", Path(nil).Pointer())
+	require.EqualValues(t, "/a/b", Path{"a", "b"}.Pointer())
+	require.EqualValues(t, "/~01/a~1b/", Path{"~1", "a/b", ""}.Pointer())
+}
+
+func TestParsePointer(t *testing.T) {
+	path, err := ParsePointer("")
+	require.NoError(t, err)
+	require.Nil(t, path)
+
+	path, err = ParsePointer("/")
+	require.NoError(t, err)
+	require.EqualValues(t, Path{""}, path)
+
+	path, err = ParsePointer("/a/b")
+	require.NoError(t, err)
+	require.EqualValues(t, Path{"a", "b"}, path)
+
+	// ~01 must be unescaped to ~1, not to /
+	path, err = ParsePointer("/~01/a~1b/~0~0")
+	require.NoError(t, err)
+	require.EqualValues(t, Path{"~1", "a/b", "~~"}, path)
+
+	for _, pointer := range []string{"a", "/a~", "/~2", "/a/~a"} {
+		_, err = ParsePointer(pointer)
+		require.True(t, errors.Is(err, ErrInvalidPointer), "pointer %q", pointer)
+	}
+
+	// Round trip
+	for _, path := range []Path{{"a"}, {"a/b", "~", "~1"}, {"", ""}} {
+		parsed, err := ParsePointer(path.Pointer())
+		require.NoError(t, err)
+		require.EqualValues(t, path, parsed)
+	}
+}
+
+func TestPath_IndexedString(t *testing.T) {
+	require.EqualValues(t, "", Path{}.IndexedString(":"))
+	require.EqualValues(t, "", Path{""}.IndexedString(":"))
+	require.EqualValues(t, "Servers[2]:Name", Path{"Servers"}.Index(2).Child("Name").IndexedString(""))
+	require.EqualValues(t, "[0][1].a\\.b", Path{"0", "1", "a.b"}.IndexedString("."))
+	require.EqualValues(t, "a.01", Path{"a", "01"}.IndexedString("."))
+}
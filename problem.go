@@ -0,0 +1,113 @@
+package errortree
+
+import (
+	"encoding/json"
+)
+
+// ProblemContentType is the media type of problem details documents.
+const ProblemContentType = "application/problem+json"
+
+// Problem represents a problem details document, as defined by RFC 9457.
+//
+// The errors of a tree are reported using the invalid-params extension member.
+type Problem struct {
+	// Type is a URI reference identifying the problem type
+	Type string `json:"type,omitempty"`
+	// Title is a short, human-readable summary of the problem type
+	Title string `json:"title,omitempty"`
+	// Status is the HTTP status code
+	Status int `json:"status,omitempty"`
+	// Detail is a human-readable explanation specific to this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// Instance is a URI reference identifying this occurrence of the problem
+	Instance string `json:"instance,omitempty"`
+	// InvalidParams holds one entry for every error in the tree
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam represents a single error of a tree inside a problem details document.
+type InvalidParam struct {
+	// Name holds the flattened key of the error
+	Name string `json:"name"`
+	// Reason holds the error message
+	Reason string `json:"reason"`
+	// Code holds the code of the error, see RegisterError
+	Code string `json:"code,omitempty"`
+}
+
+// ProblemOptions holds the options for a problem details document created by NewProblem.
+type ProblemOptions struct {
+	// Type is a URI reference identifying the problem type
+	Type string
+	// Title is a short, human-readable summary of the problem type
+	Title string
+	// Status is the HTTP status code
+	Status int
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	// If empty and the provided error is not a *Tree, the error message is used.
+	Detail string
+	// Instance is a URI reference identifying this occurrence of the problem
+	Instance string
+}
+
+// NewProblem returns a problem details document describing the given error.
+//
+// If the error is a *Tree, every error in the tree is reported as an invalid
+// parameter, named after its flattened key. The errors of a MultiError are reported
+// as separate invalid parameters of the same name. The invalid parameters are sorted in
+// the same way as the keys returned by Keys.
+func NewProblem(err error, options ProblemOptions) *Problem {
+	problem := &Problem{
+		Type:     options.Type,
+		Title:    options.Title,
+		Status:   options.Status,
+		Detail:   options.Detail,
+		Instance: options.Instance,
+	}
+
+	tree, isTree := GetTree(err)
+	if !isTree {
+		if problem.Detail == "" && err != nil {
+			problem.Detail = err.Error()
+		}
+		return problem
+	}
+
+	for _, leaf := range sortedLeaves(tree) {
+		for _, err := range splitErrors(leaf.err) {
+			problem.InvalidParams = append(problem.InvalidParams, InvalidParam{
+				Name:   leaf.Key(),
+				Reason: err.Error(),
+				Code:   codeOf(err),
+			})
+		}
+	}
+
+	return problem
+}
+
+// Tree rebuilds an error tree from the invalid parameters of the problem details document.
+//
+// The names of the invalid parameters are split into paths using the given delimiter,
+// like Unflatten does. Invalid parameters sharing a name are combined into a MultiError.
+// Reasons carrying the code of a registered error are rehydrated,
+// see RegisterError.
+func (p *Problem) Tree(delimiter string) *Tree {
+	errorMap := make(map[string]error, len(p.InvalidParams))
+	for _, param := range p.InvalidParams {
+		errorMap[param.Name] = appendError(errorMap[param.Name], decodeError(param.Reason, param.Code))
+	}
+
+	return Unflatten(errorMap, delimiter)
+}
+
+// ParseProblem decodes a problem details document and rebuilds an error tree
+// from its invalid parameters, as done by Problem.Tree.
+func ParseProblem(data []byte, delimiter string) (*Tree, error) {
+	var problem Problem
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return nil, err
+	}
+
+	return problem.Tree(delimiter), nil
+}
@@ -0,0 +1,90 @@
+package errortree
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProblem(t *testing.T) {
+	sentinel := errors.New("missing")
+	RegisterError("problem_missing", sentinel)
+
+	options := ProblemOptions{
+		Type:     "https://example.com/probs/validation",
+		Title:    "Validation failed",
+		Status:   422,
+		Instance: "/config",
+	}
+
+	tree := SetPath(nil, Path{"Network", "ListenAddress"}, sentinel)
+	tree = SetPath(tree, Path{"Network", "MaxClients"}, errors.New("Must be at least 1"))
+	tree = Set(tree, "host:port", errors.New("invalid"))
+
+	encoded, err := json.Marshal(NewProblem(tree, options))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "https://example.com/probs/validation",
+		"title": "Validation failed",
+		"status": 422,
+		"instance": "/config",
+		"invalid-params": [
+			{"name": "Network:ListenAddress", "reason": "missing", "code": "problem_missing"},
+			{"name": "Network:MaxClients", "reason": "Must be at least 1"},
+			{"name": "host\\:port", "reason": "invalid"}
+		]
+	}`, string(encoded))
+
+	// Non-tree errors are reported as detail, unless detail is provided
+	require.EqualValues(t, &Problem{Status: 500, Detail: "test"}, NewProblem(errors.New("test"), ProblemOptions{Status: 500}))
+	require.EqualValues(t, &Problem{Detail: "detail"}, NewProblem(errors.New("test"), ProblemOptions{Detail: "detail"}))
+	require.EqualValues(t, &Problem{}, NewProblem(nil, ProblemOptions{}))
+}
+
+func TestParseProblem(t *testing.T) {
+	sentinel := errors.New("missing")
+	RegisterError("parse_problem_missing", sentinel)
+
+	tree, err := ParseProblem([]byte(`{
+		"type": "https://example.com/probs/validation",
+		"status": 422,
+		"invalid-params": [
+			{"name": "Network.ListenAddress", "reason": "missing", "code": "parse_problem_missing"},
+			{"name": "Network.MaxClients", "reason": "Must be at least 1"},
+			{"name": "host\\.port", "reason": "invalid"}
+		]
+	}`), ".")
+	require.NoError(t, err)
+	require.EqualValues(t, ".", tree.Delimiter)
+	require.EqualValues(t, []string{"Network.ListenAddress", "Network.MaxClients", `host\.port`}, Keys(tree))
+	require.Equal(t, sentinel, Get(tree, "Network", "ListenAddress"))
+	require.EqualError(t, Get(tree, "host.port"), "invalid")
+
+	_, err = ParseProblem([]byte(`[]`), "")
+	require.Error(t, err)
+}
+
+func TestProblem_roundTrip(t *testing.T) {
+	tree := SetPath(nil, Path{"a", "b"}, errors.New("test0"))
+	tree = SetPath(tree, Path{"c"}, errors.New("test1"))
+
+	encoded, err := json.Marshal(NewProblem(tree, ProblemOptions{}))
+	require.NoError(t, err)
+	decoded, err := ParseProblem(encoded, DefaultDelimiter)
+	require.NoError(t, err)
+	require.EqualValues(t, Flatten(tree), Flatten(decoded))
+}
+
+func TestProblem_multiErrors(t *testing.T) {
+	tree := Append(nil, "a", errors.New("test0"))
+	tree = Append(tree, "a", errors.New("test1"))
+
+	problem := NewProblem(tree, ProblemOptions{})
+	require.EqualValues(t, []InvalidParam{
+		{Name: "a", Reason: "test0"},
+		{Name: "a", Reason: "test1"},
+	}, problem.InvalidParams)
+	require.EqualValues(t, Flatten(tree), Flatten(problem.Tree("")))
+}
@@ -0,0 +1,75 @@
+package errortree
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IsIndex reports whether the given key represents an index, as created by Path.Index.
+//
+// A key represents an index if it is the decimal representation of a
+// non-negative integer without leading zeros.
+func IsIndex(key string) bool {
+	index, err := strconv.Atoi(key)
+	return err == nil && index >= 0 && strconv.Itoa(index) == key
+}
+
+// sortedKeys returns the keys of the given error map in natural order.
+func sortedKeys(errorMap map[string]error) []string {
+	keys := make([]string, 0, len(errorMap))
+	for key := range errorMap {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	return keys
+}
+
+// sortKeys sorts the given keys in natural order.
+func sortKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		return naturalLess(keys[i], keys[j])
+	})
+}
+
+// naturalLess reports whether a is ordered before b in natural order.
+//
+// Natural order equals alphabetical order, except that sequences of digits
+// are compared by their numeric value, so "a2" is ordered before "a10".
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		digitsA, digitsB := countDigits(a), countDigits(b)
+		if digitsA == 0 || digitsB == 0 {
+			if a[0] != b[0] {
+				return a[0] < b[0]
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		// Compare numbers by their number of significant digits first
+		numberA := strings.TrimLeft(a[:digitsA], "0")
+		numberB := strings.TrimLeft(b[:digitsB], "0")
+		if len(numberA) != len(numberB) {
+			return len(numberA) < len(numberB)
+		} else if numberA != numberB {
+			return numberA < numberB
+		} else if digitsA != digitsB {
+			// Equal numbers with fewer leading zeros come first
+			return digitsA < digitsB
+		}
+		a, b = a[digitsA:], b[digitsB:]
+	}
+
+	return len(a) < len(b)
+}
+
+// countDigits returns the number of leading decimal digits in s.
+func countDigits(s string) int {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,61 @@
+package errortree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsIndex(t *testing.T) {
+	require.True(t, IsIndex("0"))
+	require.True(t, IsIndex("10"))
+	require.False(t, IsIndex(""))
+	require.False(t, IsIndex("01"))
+	require.False(t, IsIndex("-1"))
+	require.False(t, IsIndex("+1"))
+	require.False(t, IsIndex("1a"))
+}
+
+func TestNaturalLess(t *testing.T) {
+	keys := []string{
+		"Servers:10",
+		"b",
+		"Servers:2:Name",
+		"Servers:2",
+		"a10b",
+		"a2b",
+		"a02b",
+		"a2",
+		"99999999999999999999999",
+		"100000000000000000000000",
+		"a",
+		"",
+	}
+	sortKeys(keys)
+	require.EqualValues(t, []string{
+		"",
+		"99999999999999999999999",
+		"100000000000000000000000",
+		"Servers:2",
+		"Servers:2:Name",
+		"Servers:10",
+		"a",
+		"a2",
+		"a2b",
+		"a02b",
+		"a10b",
+		"b",
+	}, keys)
+
+	require.False(t, naturalLess("a", "a"))
+	require.False(t, naturalLess("a10", "a10"))
+}
+
+func TestSortedKeys(t *testing.T) {
+	require.EqualValues(t, []string{"1", "2", "10"}, sortedKeys(map[string]error{
+		"10": errors.New("test0"),
+		"2":  errors.New("test1"),
+		"1":  errors.New("test2"),
+	}))
+}
@@ -0,0 +1,187 @@
+package errortree
+
+import (
+	"sync"
+)
+
+var _ error = (*SyncTree)(nil)
+
+// SyncTree is a concurrency-safe error tree, which may be shared between goroutines.
+//
+// All mutations and reads are guarded by a lock. Reads, like Error, Flatten and Keys,
+// operate on a consistent snapshot of the tree.
+// The package-level functions Set, Add, Append, their path variants and SetNodeError
+// accept a *SyncTree as parent, mutate it while holding the lock and return the
+// *SyncTree itself. TrySet, TryAdd and their path variants mutate it likewise, but
+// return a snapshot of it. Other package-level functions only handle a *Tree, Snapshot
+// may be used for passing the current state of the tree to them.
+//
+// The zero value is an empty tree using the default settings, ready to use.
+// NewSyncTree may be used for specifying the settings of the tree.
+type SyncTree struct {
+	mu   sync.RWMutex
+	tree *Tree
+}
+
+// NewSyncTree returns a new concurrency-safe tree wrapping the given tree.
+//
+// The given tree specifies the settings of the tree, like its delimiter, and
+// must not be accessed directly afterwards. If tree is nil, a new tree is created.
+func NewSyncTree(tree *Tree) *SyncTree {
+	if tree == nil {
+		tree = New()
+	}
+
+	return &SyncTree{
+		tree: tree,
+	}
+}
+
+// mutate invokes fn with the wrapped tree while holding the write lock.
+//
+// The wrapped tree is created on first use.
+func (t *SyncTree) mutate(fn func(tree *Tree)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tree == nil {
+		t.tree = New()
+	}
+	fn(t.tree)
+}
+
+// Set creates or replaces an error under a given key, like Set does.
+func (t *SyncTree) Set(key string, err error) {
+	t.SetPath(Path{key}, err)
+}
+
+// SetPath creates or replaces an error under a given path, like SetPath does.
+func (t *SyncTree) SetPath(path Path, err error) {
+	SetPath(t, path, err)
+}
+
+// Add adds an error under a given key, like Add does.
+//
+// This function panics if the key is already present in the tree.
+func (t *SyncTree) Add(key string, err error) {
+	t.AddPath(Path{key}, err)
+}
+
+// AddPath adds an error under a given path, like AddPath does.
+//
+// This function panics if the path is already present in the tree.
+func (t *SyncTree) AddPath(path Path, err error) {
+	AddPath(t, path, err)
+}
+
+// Append adds an error under a given key, keeping any error already present
+// under the key, like Append does.
+func (t *SyncTree) Append(key string, err error) {
+	t.AppendPath(Path{key}, err)
+}
+
+// AppendPath adds an error under a given path, keeping any error already present
+// under the path, like AppendPath does.
+func (t *SyncTree) AppendPath(path Path, err error) {
+	AppendPath(t, path, err)
+}
+
+// TrySet creates or replaces an error under a given key, like TrySet does.
+func (t *SyncTree) TrySet(key string, err error) error {
+	return t.TrySetPath(Path{key}, err)
+}
+
+// TrySetPath creates or replaces an error under a given path, like TrySetPath does.
+func (t *SyncTree) TrySetPath(path Path, err error) (setErr error) {
+	t.mutate(func(tree *Tree) {
+		_, setErr = TrySetPath(tree, path, err)
+	})
+	return
+}
+
+// TryAdd adds an error under a given key, like TryAdd does.
+func (t *SyncTree) TryAdd(key string, err error) error {
+	return t.TryAddPath(Path{key}, err)
+}
+
+// TryAddPath adds an error under a given path, like TryAddPath does.
+func (t *SyncTree) TryAddPath(path Path, err error) (addErr error) {
+	t.mutate(func(tree *Tree) {
+		_, addErr = TryAddPath(tree, path, err)
+	})
+	return
+}
+
+// SetNodeError sets the node-level error of the tree under the given path,
+// like SetNodeError does.
+func (t *SyncTree) SetNodeError(path Path, err error) {
+	SetNodeError(t, path, err)
+}
+
+// Get retrieves the error for the given key, like Get does.
+//
+// Returned trees are snapshots, which are not affected by later mutations.
+func (t *SyncTree) Get(key string, path ...string) error {
+	return t.GetPath(append(Path{key}, path...))
+}
+
+// GetPath retrieves the error for the given path, like GetPath does.
+//
+// Returned trees are snapshots, which are not affected by later mutations.
+func (t *SyncTree) GetPath(path Path) (err error) {
+	// Retrieving errors may initialize the trees on the path, so the write lock is required
+	t.mutate(func(tree *Tree) {
+		err = GetPath(tree, path)
+		if childTree, isTree := GetTree(err); isTree {
+			err = copyTree(childTree, make(map[*Tree]*Tree))
+		}
+	})
+	return
+}
+
+// Snapshot returns a deep copy of the current state of the tree.
+//
+// The returned tree is not affected by later mutations and may be passed to
+// the package-level functions.
+func (t *SyncTree) Snapshot() *Tree {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.tree == nil {
+		return New()
+	}
+	return copyTree(t.tree, make(map[*Tree]*Tree))
+}
+
+// Flatten returns the flattened errors of a snapshot of the tree, like Flatten does.
+func (t *SyncTree) Flatten() map[string]error {
+	return Flatten(t.Snapshot())
+}
+
+// Keys returns the keys of a snapshot of the tree, like Keys does.
+func (t *SyncTree) Keys() []string {
+	return Keys(t.Snapshot())
+}
+
+func (t *SyncTree) Error() string {
+	return t.Snapshot().Error()
+}
+
+// ErrorOrNil returns nil if the tree is empty or a snapshot of the tree otherwise.
+func (t *SyncTree) ErrorOrNil() error {
+	return t.Snapshot().ErrorOrNil()
+}
+
+// Dropped returns the number of errors which have been dropped due to the Limit
+// of the wrapped tree, like Tree.Dropped does.
+func (t *SyncTree) Dropped() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tree.Dropped()
+}
+
+// Unwrap returns all errors contained in a snapshot of the tree, like Tree.Unwrap does.
+func (t *SyncTree) Unwrap() []error {
+	return t.Snapshot().Unwrap()
+}
@@ -0,0 +1,105 @@
+package errortree
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncTree(t *testing.T) {
+	tree := NewSyncTree(nil)
+	require.Nil(t, tree.ErrorOrNil())
+
+	tree.Set("a", errors.New("test0"))
+	tree.SetPath(Path{"b", "c"}, errors.New("test1"))
+	tree.Append("a", errors.New("test2"))
+	tree.SetNodeError(Path{"b"}, errors.New("test3"))
+
+	require.EqualValues(t, []string{"a", "b", "b:c"}, tree.Keys())
+	require.EqualValues(t, map[string]error{
+		"a":   MultiError{errors.New("test0"), errors.New("test2")},
+		"b":   errors.New("test3"),
+		"b:c": errors.New("test1"),
+	}, tree.Flatten())
+	require.EqualValues(t, "4 errors occurred:\n\n* a: test0\n* a: test2\n* b: test3\n* b:c: test1", tree.Error())
+	require.EqualError(t, tree.Get("b", "c"), "test1")
+
+	// Add and TryAdd report existing keys
+	require.PanicsWithValue(t, "Cannot add error: key b:c exists.", func() {
+		tree.AddPath(Path{"b", "c"}, errors.New("test"))
+	})
+	require.True(t, errors.Is(tree.TryAdd("a", errors.New("test")), ErrKeyExists))
+	require.NoError(t, tree.TrySet("a", errors.New("test4")))
+	require.EqualError(t, tree.Get("a"), "test4")
+
+	// Package-level functions mutate the tree while holding its lock
+	require.Equal(t, tree, Set(tree, "d", errors.New("test5")))
+	require.Equal(t, tree, SetNodeError(tree, nil, errors.New("test6")))
+	require.EqualError(t, tree.Get("d"), "test5")
+
+	// TrySet and TryAdd mutate the tree while holding its lock and return a snapshot
+	snapshot, err := TrySet(tree, "f", errors.New("test9"))
+	require.NoError(t, err)
+	require.EqualError(t, Get(snapshot, "f"), "test9")
+	require.EqualError(t, tree.Get("f"), "test9")
+	_, err = TryAddPath(tree, Path{"b", "c"}, errors.New("test"))
+	require.True(t, errors.Is(err, ErrKeyExists))
+	require.EqualError(t, tree.Get("b", "c"), "test1")
+
+	// Snapshots are not affected by later mutations
+	snapshot = tree.Snapshot()
+	subTree := tree.Get("b").(*Tree)
+	tree.Set("e", errors.New("test7"))
+	tree.SetPath(Path{"b", "d"}, errors.New("test8"))
+	require.Nil(t, Get(snapshot, "e"))
+	require.Nil(t, Get(subTree, "d"))
+	require.EqualError(t, snapshot.NodeError, "test6")
+
+	// The settings of the wrapped tree are used
+	tree = NewSyncTree(&Tree{Delimiter: "."})
+	tree.SetPath(Path{"a", "b"}, errors.New("test"))
+	require.EqualValues(t, []string{"a.b"}, tree.Keys())
+	require.True(t, errors.Is(tree, Get(tree.Snapshot(), "a", "b")))
+}
+
+func TestSyncTree_zeroValue(t *testing.T) {
+	var tree SyncTree
+	require.Nil(t, tree.ErrorOrNil())
+	require.Empty(t, tree.Keys())
+	require.EqualValues(t, 0, tree.Dropped())
+
+	tree.Set("a", errors.New("test"))
+	require.EqualValues(t, []string{"a"}, tree.Keys())
+	require.EqualError(t, tree.Get("a"), "test")
+
+	var other SyncTree
+	snapshot, err := TrySet(&other, "b", errors.New("test"))
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"b"}, Keys(snapshot))
+	require.EqualValues(t, []string{"b"}, other.Keys())
+}
+
+func TestSyncTree_concurrent(t *testing.T) {
+	tree := NewSyncTree(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			key := strconv.Itoa(i)
+			tree.SetPath(Path{"a", key}, errors.New("test"))
+			Append(tree, "b", errors.New(key))
+			_ = tree.Error()
+			_ = tree.Keys()
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, tree.Flatten(), 51)
+	require.Len(t, tree.Get("b"), 50)
+}
@@ -6,15 +6,71 @@ import (
 
 var _ error = (*Tree)(nil)
 
+// Ordering defines the order in which the errors of a tree are reported.
+type Ordering int
+
+const (
+	// NaturalOrder reports errors sorted by their keys in natural order.
+	NaturalOrder Ordering = iota
+	// InsertionOrder reports errors in the order in which they were added to the tree.
+	// Errors added to the Errors map directly are reported last, in natural order.
+	InsertionOrder
+)
+
 // Tree is an error type which acts as a container for storing
 // multiple errors in a tree structure.
 type Tree struct {
 	// Errors holds the tree's items
 	Errors map[string]error
-	// Delimiter specifies the tree's delimiter for building nested paths
+	// NodeError optionally holds an error applying to the tree itself,
+	// in addition to the errors held by the tree
+	NodeError error
+	// Delimiter specifies the tree's delimiter for building nested paths.
+	// It must not start with a backslash, which is used for escaping delimiters inside of keys.
 	Delimiter string
-	// Formatter specifies the formatter to use when Error is invoked
+	// Formatter specifies the formatter to use when Error is invoked.
+	//
+	// A Formatter only receives the flattened errors, so it cannot take the order
+	// configured for the tree into account. If both Formatter and TreeFormatter are nil,
+	// SimpleTreeFormatter is used, which reports errors in the order configured for the tree.
 	Formatter Formatter
+	// TreeFormatter specifies the formatter to use when Error is invoked,
+	// taking precedence over Formatter if set
+	TreeFormatter TreeFormatter
+	// Ordering specifies the order in which errors are reported.
+	//
+	// The ordering configured for the top-level tree is used throughout the complete tree.
+	// It is used by Keys, WrappedErrors, Error with the default formatter and by
+	// TreeFormatters like SimpleTreeFormatter. A Formatter only receives the
+	// flattened errors, so it cannot take the ordering into account.
+	Ordering Ordering
+	// Less optionally specifies a comparator, which reports whether the error a
+	// should be reported before the error b. If set, it takes precedence over Ordering,
+	// which is only used for ordering errors the comparator considers equal.
+	//
+	// When ordering the errors of a single tree, like WrappedErrors does, the compared
+	// errors may be nested trees. Otherwise, only errors which are not trees are compared.
+	// Like Ordering, the comparator of the top-level tree is used throughout the complete tree
+	// and by the same functions and formatters.
+	Less func(a, b *PathError) bool
+	// Limit optionally specifies the maximum number of errors stored in the tree.
+	//
+	// Once the limit is reached, errors passed to Set, Add, Append, their variants and
+	// SetNodeError are counted, but not stored. The number of dropped errors is reported by Dropped
+	// and by the TreeFormatters provided by this package, which includes the message
+	// returned by Error with the default formatter.
+	// Only errors stored using these functions with this tree as parent are counted
+	// towards the limit. Zero means no limit.
+	Limit int
+
+	// order holds the keys in the order in which they were added
+	order []string
+	// stored holds the number of errors stored in the tree, as counted for the limit
+	stored int
+	// dropped holds the number of errors which have been dropped due to the limit
+	dropped int
+	// frozen specifies whether the tree has been frozen
+	frozen bool
 }
 
 func (t *Tree) getErrors() map[string]error {
@@ -24,6 +80,15 @@ func (t *Tree) getErrors() map[string]error {
 	return t.Errors
 }
 
+// put stores an error under the given key, keeping track of the insertion order.
+func (t *Tree) put(key string, err error) {
+	errors := t.getErrors()
+	if _, keyExists := errors[key]; !keyExists {
+		t.order = append(t.order, key)
+	}
+	errors[key] = err
+}
+
 func (t *Tree) getDelimiter() string {
 	if t.Delimiter == "" {
 		t.Delimiter = DefaultDelimiter
@@ -32,26 +97,56 @@ func (t *Tree) getDelimiter() string {
 	return t.Delimiter
 }
 
-func (t *Tree) getFormatter() Formatter {
-	if t.Formatter == nil {
-		t.Formatter = SimpleFormatter
+func (t *Tree) Error() string {
+	if t == nil {
+		return ""
 	}
-	return t.Formatter
+	if t.TreeFormatter != nil {
+		return t.TreeFormatter(t)
+	}
+	if t.Formatter != nil {
+		return t.Formatter(flatten(t))
+	}
+
+	return SimpleTreeFormatter(t)
 }
 
-func (t *Tree) Error() string {
+// Dropped returns the number of errors which have been dropped due to the Limit
+// of the tree or any tree nested inside of it.
+func (t *Tree) Dropped() int {
 	if t == nil {
-		return ""
+		return 0
+	}
+	return dropped(t, nil)
+}
+
+func dropped(tree *Tree, visited []*Tree) int {
+	visited = append(visited, tree)
+
+	count := tree.dropped
+	for _, err := range tree.Errors {
+		childTree, isTree := GetTree(err)
+		if !isTree {
+			continue
+		}
+
+		// Skip recursive references
+		if isVisited(childTree, visited) {
+			continue
+		}
+		count += dropped(childTree, visited)
 	}
-	formatter := t.getFormatter()
 
-	return formatter(flatten(t, t.getDelimiter(), nil))
+	return count
 }
 
 // ErrorOrNil returns nil if the tree is empty or the tree itself
 // otherwise.
+//
+// A tree is not considered empty if errors have been dropped due to its Limit,
+// even if none of them have been stored.
 func (t *Tree) ErrorOrNil() error {
-	if t == nil || len(t.Errors) == 0 {
+	if t == nil || (len(t.Errors) == 0 && t.NodeError == nil && t.Dropped() == 0) {
 		return nil
 	}
 	return t
@@ -59,32 +154,223 @@ func (t *Tree) ErrorOrNil() error {
 
 // WrappedErrors returns the errors wrapped by the tree.
 //
-// The ordering of the returned errors is determined by the alphabetical
-// ordering of the corresponding error keys.
+// The ordering of the returned errors is determined by the tree's Ordering.
+// If set, the tree's node-level error is returned first.
 func (t *Tree) WrappedErrors() []error {
 	errors := t.getErrors()
-	wrappedErrors := make([]error, len(errors))
+	wrappedErrors := make([]error, 0, len(errors)+1)
+	if t.NodeError != nil {
+		wrappedErrors = append(wrappedErrors, t.NodeError)
+	}
+	for _, key := range t.orderedKeys(t, nil) {
+		wrappedErrors = append(wrappedErrors, errors[key])
+	}
+
+	return wrappedErrors
+}
+
+// orderedKeys returns the keys of the given tree's errors, ordered according to
+// the settings of t. The path parameter specifies the path of the given tree.
+func (t *Tree) orderedKeys(tree *Tree, path Path) []string {
+	errors := tree.getErrors()
+	keys := t.baseOrderedKeys(tree)
+
+	if t.Less != nil {
+		children := make([]*PathError, len(keys))
+		for i, key := range keys {
+			children[i] = &PathError{
+				path:      path.Child(key),
+				delimiter: t.getDelimiter(),
+				err:       errors[key],
+			}
+		}
+		sort.SliceStable(children, func(i, j int) bool {
+			return t.Less(children[i], children[j])
+		})
+		for i, child := range children {
+			keys[i] = child.path[len(path)]
+		}
+	}
+
+	return keys
+}
+
+// baseOrderedKeys returns the keys of the given tree's errors, ordered according
+// to the Ordering of t, ignoring the comparator.
+func (t *Tree) baseOrderedKeys(tree *Tree) []string {
+	if t.Ordering == InsertionOrder {
+		return insertionOrderedKeys(tree)
+	}
+	return sortedKeys(tree.getErrors())
+}
+
+// insertionOrderedKeys returns the keys of the given tree's errors in the order
+// in which they were added.
+func insertionOrderedKeys(tree *Tree) []string {
+	errors := tree.getErrors()
 	keys := make([]string, 0, len(errors))
+	seen := make(map[string]bool, len(errors))
+	for _, key := range tree.order {
+		// Skip keys which have been removed from the map or are recorded twice
+		if _, keyExists := errors[key]; keyExists && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+
+	// Report keys which have been added to the map directly last
+	var remaining []string
 	for key := range errors {
-		keys = append(keys, key)
+		if !seen[key] {
+			remaining = append(remaining, key)
+		}
+	}
+	sortKeys(remaining)
+
+	return append(keys, remaining...)
+}
+
+// Unwrap returns all errors contained in the tree, which allows
+// errors.Is and errors.As to match errors stored anywhere inside the tree.
+//
+// Nested trees are not returned themselves, but are replaced by the errors they
+// contain. Each returned error is a *PathError, which carries the location of
+// the error inside the tree.
+// The ordering of the returned errors is the same as for the keys returned by Keys.
+func (t *Tree) Unwrap() []error {
+	if t == nil {
+		return nil
 	}
-	sort.Strings(keys)
-	for i, key := range keys {
-		wrappedErrors[i] = errors[key]
+
+	leaves := sortedLeaves(t)
+	unwrapped := make([]error, len(leaves))
+	for i, leaf := range leaves {
+		unwrapped[i] = leaf
 	}
 
-	return wrappedErrors
+	return unwrapped
 }
 
 // New returns a new error tree.
 func New() *Tree {
 	return &Tree{
 		Delimiter: DefaultDelimiter,
-		Formatter: SimpleFormatter,
 		Errors:    make(map[string]error),
 	}
 }
 
+// newChild returns a new error tree which inherits the settings of its parent.
+func newChild(parent *Tree) *Tree {
+	child := New()
+	child.Delimiter = parent.getDelimiter()
+	child.Formatter = parent.Formatter
+	child.TreeFormatter = parent.TreeFormatter
+	child.Ordering = parent.Ordering
+	child.Less = parent.Less
+
+	return child
+}
+
+// Clone returns a deep copy of the tree, including all nested trees.
+//
+// The settings of the tree and its nested trees, like the delimiter and formatter,
+// are retained. The returned tree is not frozen, even if the tree is.
+func (t *Tree) Clone() *Tree {
+	if t == nil {
+		return nil
+	}
+	return copyTree(t, make(map[*Tree]*Tree))
+}
+
+// Freeze freezes the tree and all nested trees.
+//
+// Set, Add, Append, their path variants and SetNodeError panic when modifying a
+// frozen tree, while TrySet, TryAdd and their path variants return ErrFrozen.
+// As the Errors map is accessible, modifying it directly is not prevented.
+// Clone may be used for creating a modifiable copy of a frozen tree.
+func (t *Tree) Freeze() {
+	if t == nil {
+		return
+	}
+	initTree(t, true, nil)
+}
+
+// initTree initializes the settings of the given tree and all nested trees,
+// so reading them does not modify them later on, which allows sharing them.
+// If freeze is true, the trees are frozen as well.
+func initTree(tree *Tree, freeze bool, visited []*Tree) {
+	visited = append(visited, tree)
+
+	tree.getErrors()
+	tree.getDelimiter()
+	if freeze {
+		tree.frozen = true
+	}
+
+	for _, err := range tree.Errors {
+		childTree, isTree := GetTree(err)
+		if !isTree {
+			continue
+		}
+
+		// Skip recursive references
+		if isVisited(childTree, visited) {
+			continue
+		}
+		initTree(childTree, freeze, visited)
+	}
+}
+
+// Frozen reports whether the tree has been frozen using Freeze.
+func (t *Tree) Frozen() bool {
+	return t != nil && t.frozen
+}
+
+// copyTree returns a deep copy of the given tree, including all nested trees.
+//
+// The copies map holds the trees which have already been copied, so recursive
+// references are retained instead of being followed endlessly.
+func copyTree(tree *Tree, copies map[*Tree]*Tree) *Tree {
+	if copied, exists := copies[tree]; exists {
+		return copied
+	}
+
+	copied := &Tree{
+		Errors:        make(map[string]error, len(tree.Errors)),
+		NodeError:     tree.NodeError,
+		Delimiter:     tree.Delimiter,
+		Formatter:     tree.Formatter,
+		TreeFormatter: tree.TreeFormatter,
+		Ordering:      tree.Ordering,
+		Less:          tree.Less,
+		Limit:         tree.Limit,
+		order:         append([]string(nil), tree.order...),
+		stored:        tree.stored,
+		dropped:       tree.dropped,
+	}
+	copies[tree] = copied
+
+	for key, err := range tree.Errors {
+		if childTree, isTree := GetTree(err); isTree {
+			err = copyTree(childTree, copies)
+		}
+		copied.Errors[key] = err
+	}
+
+	return copied
+}
+
+// isVisited reports whether the given tree is part of the visited trees,
+// which is used for skipping recursive references.
+func isVisited(tree *Tree, visited []*Tree) bool {
+	for _, visitedTree := range visited {
+		if tree == visitedTree {
+			return true
+		}
+	}
+	return false
+}
+
 // GetTree returns the tree for a given error.
 func GetTree(err error) (tree *Tree, isTree bool) {
 	tree, isTree = err.(*Tree)
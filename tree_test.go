@@ -1,7 +1,10 @@
 package errortree
 
 import (
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -12,7 +15,7 @@ func TestNew(t *testing.T) {
 	tree := New()
 	require.NotNil(t, tree)
 	require.EqualValues(t, DefaultDelimiter, tree.Delimiter)
-	require.NotNil(t, tree.Formatter)
+	require.Nil(t, tree.Formatter)
 	require.NotNil(t, tree.Errors)
 
 	// Misc: validate that getErrors (internal function) initializes the Errors map
@@ -22,6 +25,7 @@ func TestNew(t *testing.T) {
 }
 
 func TestTree_ErrorOrNil(t *testing.T) {
+	require.NotNil(t, (&Tree{NodeError: errors.New("test")}).ErrorOrNil())
 	// Create new error
 	tree := &Tree{}
 	require.NotNil(t, tree)
@@ -57,6 +61,15 @@ func TestTree_WrappedErrors(t *testing.T) {
 		errors.New("a"),
 		errors.New("b"),
 	})
+
+	// The node-level error is returned first
+	tree.NodeError = errors.New("d")
+	require.EqualValues(t, []error{
+		errors.New("d"),
+		errors.New("c"),
+		errors.New("a"),
+		errors.New("b"),
+	}, tree.WrappedErrors())
 }
 
 func TestTree_Error(t *testing.T) {
@@ -93,6 +106,13 @@ func TestTree_Error(t *testing.T) {
 	// Call the formatter, but only require that formatter_called is returned
 	require.EqualValues(t, "formatter_called", tree.Error())
 
+	// The TreeFormatter takes precedence over the Formatter
+	tree.TreeFormatter = func(formattedTree *Tree) string {
+		require.Equal(t, tree, formattedTree)
+		return "tree_formatter_called"
+	}
+	require.EqualValues(t, "tree_formatter_called", tree.Error())
+
 }
 
 func TestGetTree(t *testing.T) {
@@ -106,3 +126,382 @@ func TestGetTree(t *testing.T) {
 	require.EqualValues(t, err, tree)
 	require.True(t, isTree)
 }
+
+type testError struct {
+	msg string
+}
+
+func (e *testError) Error() string {
+	return e.msg
+}
+
+func TestTree_Unwrap(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	typed := &testError{msg: "typed"}
+
+	tree := &Tree{
+		Errors: map[string]error{
+			"a": errors.New("test0"),
+			"b": &Tree{
+				Errors: map[string]error{
+					"a": &Tree{
+						Errors: map[string]error{
+							"a": sentinel,
+						},
+					},
+					"b": typed,
+				},
+			},
+		},
+	}
+
+	// Nested trees are replaced by their errors, wrapped in a *PathError
+	unwrapped := tree.Unwrap()
+	require.Len(t, unwrapped, 3)
+	for i, expected := range []struct {
+		key string
+		err error
+	}{
+		{"a", errors.New("test0")},
+		{"b:a:a", sentinel},
+		{"b:b", typed},
+	} {
+		require.IsType(t, &PathError{}, unwrapped[i])
+		require.EqualValues(t, expected.key, unwrapped[i].(*PathError).Key())
+		require.EqualValues(t, expected.err, errors.Unwrap(unwrapped[i]))
+	}
+
+	// errors.Is and errors.As traverse the complete tree
+	require.True(t, errors.Is(tree, sentinel))
+	require.False(t, errors.Is(tree, errors.New("sentinel")))
+	var target *testError
+	require.True(t, errors.As(tree, &target))
+	require.Equal(t, typed, target)
+
+	// The location of a matched error is available through errors.As, too
+	var pathErr *PathError
+	require.True(t, errors.As(tree, &pathErr))
+	require.EqualValues(t, []string{"a"}, pathErr.Path())
+
+	// Recursive trees must not cause endless recursion
+	tree.Errors["c"] = tree
+	require.True(t, errors.Is(tree, sentinel))
+	require.False(t, errors.Is(tree, errors.New("test1")))
+
+	// A nil tree does not wrap anything
+	tree = nil
+	require.Nil(t, tree.Unwrap())
+}
+
+func TestTree_Ordering(t *testing.T) {
+	tree := New()
+	tree.Ordering = InsertionOrder
+
+	Set(tree, "c", errors.New("test0"))
+	SetPath(tree, Path{"b", "z"}, errors.New("test1"))
+	SetPath(tree, Path{"b", "a"}, errors.New("test2"))
+	Set(tree, "a", errors.New("test3"))
+	// Replacing an error keeps its position
+	Set(tree, "c", errors.New("test4"))
+	// Errors added to the map directly are reported last
+	tree.Errors["e"] = errors.New("test5")
+	tree.Errors["d"] = errors.New("test6")
+
+	// Child trees inherit the ordering
+	require.EqualValues(t, InsertionOrder, tree.Errors["b"].(*Tree).Ordering)
+
+	require.EqualValues(t, []string{"c", "b:z", "b:a", "a", "d", "e"}, Keys(tree))
+	require.EqualValues(t, []error{
+		errors.New("test4"),
+		tree.Errors["b"],
+		errors.New("test3"),
+		errors.New("test6"),
+		errors.New("test5"),
+	}, tree.WrappedErrors())
+	require.EqualValues(t, "b:z", tree.Unwrap()[1].(*PathError).Key())
+
+	var walked []string
+	Walk(tree, func(path Path, err error) error {
+		walked = append(walked, path.String(":"))
+		return nil
+	})
+	require.EqualValues(t, []string{"c", "b", "b:z", "b:a", "a", "d", "e"}, walked)
+
+	// Error reports errors in the configured order with the default formatter and tree formatters
+	require.EqualValues(t, "6 errors occurred:\n\n* c: test4\n* b:z: test1\n* b:a: test2\n* a: test3\n* d: test6\n* e: test5",
+		tree.Error())
+	tree.TreeFormatter = NewJSONTreeFormatter(JSONFormatterOptions{})
+	require.EqualValues(t, `{"c":"test4","b:z":"test1","b:a":"test2","a":"test3","d":"test6","e":"test5"}`, tree.Error())
+	tree.TreeFormatter = nil
+
+	// Removed keys are skipped
+	delete(tree.Errors, "c")
+	require.EqualValues(t, []string{"b:z", "b:a", "a", "d", "e"}, Keys(tree))
+
+	// The ordering of the top-level tree applies to the complete tree
+	tree.Ordering = NaturalOrder
+	require.EqualValues(t, []string{"a", "b:a", "b:z", "d", "e"}, Keys(tree))
+}
+
+func TestTree_Ordering_defaultFormatter(t *testing.T) {
+	tree := New()
+	tree.Ordering = InsertionOrder
+	Set(tree, "b", errors.New("test0"))
+	Set(tree, "a", errors.New("test1"))
+
+	require.EqualValues(t, "2 errors occurred:\n\n* b: test0\n* a: test1", tree.Error())
+
+	// Custom formatters only receive the flattened errors
+	tree.Formatter = func(errorMap map[string]error) string {
+		require.EqualValues(t, Flatten(tree), errorMap)
+		return "formatter_called"
+	}
+	require.EqualValues(t, "formatter_called", tree.Error())
+}
+
+type severityError struct {
+	severity int
+}
+
+func (e *severityError) Error() string {
+	return "severity " + string(rune('0'+e.severity))
+}
+
+func TestTree_Less(t *testing.T) {
+	severity := func(err *PathError) int {
+		var target *severityError
+		if errors.As(err, &target) {
+			return target.severity
+		}
+		return 0
+	}
+
+	tree := New()
+	tree.Ordering = InsertionOrder
+	tree.Less = func(a, b *PathError) bool {
+		return severity(a) > severity(b)
+	}
+
+	Set(tree, "d", &severityError{1})
+	SetPath(tree, Path{"c", "b"}, &severityError{1})
+	SetPath(tree, Path{"c", "a"}, &severityError{2})
+	Set(tree, "b", &severityError{1})
+	Set(tree, "a", &severityError{3})
+
+	// Child trees inherit the comparator
+	require.NotNil(t, tree.Errors["c"].(*Tree).Less)
+
+	// Errors are sorted by severity, falling back to the insertion order
+	require.EqualValues(t, []string{"a", "c:a", "d", "c:b", "b"}, Keys(tree))
+	require.EqualValues(t, "5 errors occurred:\n\n* a: severity 3\n* c:a: severity 2\n* d: severity 1\n* c:b: severity 1\n* b: severity 1",
+		SimpleTreeFormatter(tree))
+
+	// Nested trees are compared when ordering the errors of a single tree,
+	// errors.As reports the error with the highest severity inside the tree
+	require.EqualValues(t, []error{
+		tree.Errors["a"],
+		tree.Errors["c"],
+		tree.Errors["d"],
+		tree.Errors["b"],
+	}, tree.WrappedErrors())
+
+	// Without an explicit ordering, the natural order is used as fallback
+	tree.Ordering = NaturalOrder
+	require.EqualValues(t, []string{"a", "c:a", "b", "c:b", "d"}, Keys(tree))
+
+	// The comparator is used by the default formatter and tree formatters
+	tree = New()
+	tree.Less = func(a, b *PathError) bool {
+		return a.Key() > b.Key()
+	}
+	Set(tree, "a", errors.New("test0"))
+	Set(tree, "b", errors.New("test1"))
+	require.EqualValues(t, "2 errors occurred:\n\n* b: test1\n* a: test0", tree.Error())
+	tree.TreeFormatter = NewJSONTreeFormatter(JSONFormatterOptions{})
+	require.EqualValues(t, `{"b":"test1","a":"test0"}`, tree.Error())
+}
+
+func TestTree_Limit(t *testing.T) {
+	tree := &Tree{Limit: 3}
+	for i := 0; i < 5; i++ {
+		Add(tree, strconv.Itoa(i), errors.New("test"))
+	}
+	Append(tree, "0", errors.New("test"))
+	SetPath(tree, Path{"a", "b"}, errors.New("test"))
+
+	// Dropped errors are not stored and no intermediate trees are created
+	require.EqualValues(t, []string{"0", "1", "2"}, Keys(tree))
+	require.EqualValues(t, 4, tree.Dropped())
+	require.EqualValues(t, "3 errors occurred:\n\n* 0: test\n* 1: test\n* 2: test\n... and 4 more errors", tree.Error())
+
+	// Replacing errors does not exceed the limit
+	Set(tree, "0", errors.New("test0"))
+	require.EqualError(t, Get(tree, "0"), "test0")
+	require.EqualValues(t, 4, tree.Dropped())
+
+	// Duplicate keys are still reported
+	require.PanicsWithValue(t, "Cannot add error: key 1 exists.", func() {
+		Add(tree, "1", errors.New("test"))
+	})
+
+	// Trees count with the number of errors they hold
+	tree = &Tree{Limit: 2, TreeFormatter: IndentedFormatter}
+	Set(tree, "a", errors.New("test0"))
+	Set(tree, "b", Set(Set(nil, "c", errors.New("test1")), "d", errors.New("test2")))
+	Set(tree, "a", New())
+	SetPath(tree, Path{"a", "b"}, errors.New("test3"))
+	require.EqualValues(t, []string{"a:b"}, Keys(tree))
+	require.EqualValues(t, 2, tree.Dropped())
+	require.EqualValues(t, "1 error occurred:\n\n└── a (1 error)\n    └── b: test3\n... and 2 more errors", tree.Error())
+
+	// Dropped errors of nested trees are reported as well
+	tree = &Tree{Limit: 1}
+	nested := &Tree{Limit: 1}
+	Set(nested, "a", errors.New("test"))
+	Set(nested, "b", errors.New("test"))
+	Set(tree, "c", nested)
+	require.EqualValues(t, 1, tree.Dropped())
+	require.True(t, strings.HasSuffix(tree.Error(), "\n... and 1 more error"))
+
+	// The summary is part of the formatted output, which keeps JSON valid
+	tree = &Tree{Limit: 1, TreeFormatter: NewJSONTreeFormatter(JSONFormatterOptions{})}
+	Set(tree, "a", errors.New("A"))
+	Set(tree, "b", errors.New("B"))
+	require.EqualValues(t, `{"a":"A","dropped":1}`, tree.Error())
+	require.True(t, json.Valid([]byte(tree.Error())))
+
+	// Formatters only receive the stored errors
+	tree.TreeFormatter = nil
+	tree.Formatter = func(errorMap map[string]error) string {
+		return strconv.Itoa(len(errorMap))
+	}
+	require.EqualValues(t, "1", tree.Error())
+
+	// Node-level errors count towards the limit
+	tree = &Tree{Limit: 1}
+	SetNodeError(tree, Path{"a"}, errors.New("test0"))
+	Set(tree, "b", errors.New("test1"))
+	SetNodeError(tree, nil, errors.New("test2"))
+	require.EqualValues(t, []string{"a"}, Keys(tree))
+	require.Nil(t, tree.NodeError)
+	require.EqualValues(t, 2, tree.Dropped())
+
+	// Replacing node-level errors does not exceed the limit
+	SetNodeError(tree, Path{"a"}, errors.New("test3"))
+	require.EqualError(t, Get(tree, "a").(*Tree).NodeError, "test3")
+	require.EqualValues(t, 2, tree.Dropped())
+
+	// Trees are not empty if the very first error exceeds the limit
+	tree = &Tree{Limit: 2}
+	Set(tree, "s", Set(Set(Set(nil, "a", errors.New("test")), "b", errors.New("test")), "c", errors.New("test")))
+	require.Empty(t, Keys(tree))
+	require.EqualValues(t, 3, tree.Dropped())
+	require.Equal(t, tree, tree.ErrorOrNil())
+	require.EqualValues(t, "0 errors occurred:\n\n... and 3 more errors", tree.Error())
+
+	// Without a limit, no errors are dropped
+	require.EqualValues(t, 0, (*Tree)(nil).Dropped())
+	require.EqualValues(t, 0, newIndentedTestTree().Dropped())
+}
+
+func TestTree_Clone(t *testing.T) {
+	require.Nil(t, (*Tree)(nil).Clone())
+
+	tree := &Tree{
+		Delimiter:     ".",
+		TreeFormatter: IndentedFormatter,
+		Ordering:      InsertionOrder,
+	}
+	Set(tree, "b", errors.New("test0"))
+	SetPath(tree, Path{"a", "b"}, errors.New("test1"))
+	SetNodeError(tree, Path{"a"}, errors.New("test2"))
+
+	// Recursive references are retained
+	Set(tree.Errors["a"].(*Tree), "c", tree)
+
+	clone := tree.Clone()
+	require.NotSame(t, tree, clone)
+	require.Equal(t, tree.Error(), clone.Error())
+	require.EqualValues(t, Keys(tree), Keys(clone))
+	require.EqualValues(t, ".", clone.Delimiter)
+	require.EqualValues(t, InsertionOrder, clone.Ordering)
+	require.Same(t, clone, clone.Errors["a"].(*Tree).Errors["c"])
+
+	// Modifying the clone does not affect the original tree and vice versa
+	SetPath(clone, Path{"a", "d"}, errors.New("test3"))
+	Set(tree, "e", errors.New("test4"))
+	require.Nil(t, Get(tree, "a", "d"))
+	require.Nil(t, Get(clone, "e"))
+	require.NotSame(t, tree.Errors["a"], clone.Errors["a"])
+}
+
+func TestTree_Freeze(t *testing.T) {
+	require.NotPanics(t, func() {
+		(*Tree)(nil).Freeze()
+	})
+	require.False(t, (*Tree)(nil).Frozen())
+
+	tree := Set(nil, "a", errors.New("test0")).(*Tree)
+	SetPath(tree, Path{"b", "c"}, errors.New("test1"))
+	nested := tree.Errors["b"].(*Tree)
+	require.False(t, tree.Frozen())
+
+	tree.Freeze()
+	require.True(t, tree.Frozen())
+	require.True(t, nested.Frozen())
+
+	// Modifying the tree or a nested tree: should fail
+	require.PanicsWithValue(t, "Cannot set error: tree is frozen.", func() {
+		Set(tree, "d", errors.New("test"))
+	})
+	require.PanicsWithValue(t, "Cannot set error: tree is frozen.", func() {
+		Add(nested, "d", errors.New("test"))
+	})
+	require.PanicsWithValue(t, "Cannot set error: tree is frozen.", func() {
+		AppendPath(tree, Path{"b", "c"}, errors.New("test"))
+	})
+	require.PanicsWithValue(t, "Cannot set error: tree is frozen.", func() {
+		SetNodeError(tree, Path{"b"}, errors.New("test"))
+	})
+	_, err := TrySetPath(tree, Path{"b", "d"}, errors.New("test"))
+	require.Equal(t, ErrFrozen, err)
+	_, err = TryAdd(tree, "a", errors.New("test"))
+	require.Equal(t, ErrFrozen, err)
+	require.Equal(t, ErrFrozen, tree.UnmarshalJSON([]byte(`{"errors": {}}`)))
+
+	// Frozen trees nested in other trees cannot be modified either
+	parent := Set(nil, "f", tree)
+	require.PanicsWithValue(t, "Cannot set error: tree is frozen.", func() {
+		SetPath(parent, Path{"f", "d"}, errors.New("test"))
+	})
+	Set(parent, "g", errors.New("test2"))
+	require.EqualValues(t, []string{"f:a", "f:b:c", "g"}, Keys(parent))
+	require.PanicsWithValue(t, "Cannot set error: tree is frozen.", func() {
+		Append(parent, "f", errors.New("test"))
+	})
+
+	// Frozen trees nested in other trees can be replaced, which does not modify them
+	replaced := Set(nil, "f", tree)
+	Set(replaced, "f", errors.New("test5"))
+	require.EqualError(t, Get(replaced, "f"), "test5")
+	_, err = TrySetPath(replaced, Path{"g"}, tree)
+	require.NoError(t, err)
+	_, err = TrySetPath(replaced, Path{"g"}, errors.New("test6"))
+	require.NoError(t, err)
+	require.EqualError(t, Get(replaced, "g"), "test6")
+
+	// The tree is left untouched and can be cloned for modification
+	require.EqualValues(t, map[string]error{
+		"a":   errors.New("test0"),
+		"b:c": errors.New("test1"),
+	}, Flatten(tree))
+	clone := tree.Clone()
+	require.False(t, clone.Frozen())
+	require.False(t, clone.Errors["b"].(*Tree).Frozen())
+	Set(clone, "d", errors.New("test3"))
+	require.EqualError(t, Get(clone, "d"), "test3")
+
+	// Immutable trees can be created from frozen trees
+	immutable := NewImmutableTree(tree).SetPath(Path{"b", "d"}, errors.New("test4"))
+	require.EqualValues(t, []string{"a", "b:c", "b:d"}, immutable.Keys())
+}